@@ -0,0 +1,258 @@
+package main
+
+import (
+    "context"
+    "log"
+    "net/http"
+    "strings"
+    "time"
+    _ "time/tzdata"
+
+    "github.com/aidantrabs/kultur/backend/internal/config"
+    "github.com/aidantrabs/kultur/backend/internal/db"
+    "github.com/aidantrabs/kultur/backend/internal/email"
+    "github.com/aidantrabs/kultur/backend/internal/handler"
+    "github.com/aidantrabs/kultur/backend/internal/middleware"
+    "github.com/aidantrabs/kultur/backend/internal/scheduler"
+    "github.com/aidantrabs/kultur/backend/internal/screening"
+    "github.com/aidantrabs/kultur/backend/internal/service"
+    "github.com/aidantrabs/kultur/backend/internal/storage"
+    "github.com/labstack/echo/v4"
+    echomw "github.com/labstack/echo/v4/middleware"
+)
+
+func main() {
+    ctx := context.Background()
+
+    cfg, err := config.Load()
+    if err != nil {
+        log.Fatal("failed to load config:", err)
+    }
+
+    pool, err := db.Connect(ctx, cfg.DatabaseURL)
+    if err != nil {
+        log.Fatal("failed to connect to database:", err)
+    }
+    defer pool.Close()
+
+    loc, err := time.LoadLocation(cfg.Timezone)
+    if err != nil {
+        log.Fatal("failed to load timezone:", err)
+    }
+
+    queries := db.New(pool)
+
+    sender, err := email.NewSender(email.SenderConfig{
+        Backend:      cfg.EmailBackend,
+        ResendAPIKey: cfg.ResendAPIKey,
+        SMTPHost:     cfg.SMTPHost,
+        SMTPPort:     cfg.SMTPPort,
+        SMTPUsername: cfg.SMTPUsername,
+        SMTPPassword: cfg.SMTPPassword,
+        FileDir:      cfg.EmailFileDir,
+    })
+    if err != nil {
+        log.Fatal("failed to configure email:", err)
+    }
+    if sender == nil {
+        log.Printf("email disabled, set EMAIL_BACKEND or RESEND_API_KEY to send email")
+    }
+
+    emailSvc := email.NewService(email.Config{
+        Sender:    sender,
+        FromEmail: cfg.FromEmail,
+        BaseURL:   cfg.BaseURL,
+    })
+
+    // outgoing email is queued and delivered by the outbox worker, which runs
+    // on every instance since claims skip rows another worker holds
+    outboxSvc := service.NewOutboxService(queries, emailSvc, cfg.EmailMaxAttempts)
+    emailSvc.UseQueue(outboxSvc)
+    outboxSvc.Run(ctx, 30*time.Second)
+
+    // memory submissions are scored before they are stored, see internal/screening
+    screener := screening.NewPipeline(cfg.ScreeningFlagScore, cfg.ScreeningRejectScore,
+        screening.Honeypot{},
+        screening.Length{Max: cfg.MemoryMaxLength},
+        screening.Links{Allowed: 1, PerLink: 20, NameLink: 50},
+        screening.BannedWords{Words: append(screening.DefaultBannedWords, cfg.ScreeningBannedWords...), PerWord: 40},
+        screening.Duplicate{Seen: queries.ContentHashExists, Points: 60},
+    )
+
+    festivalSvc := service.NewFestivalService(pool, queries)
+    memorySvc := service.NewMemoryService(pool, queries, festivalSvc, emailSvc, screener)
+    subscriptionSvc := service.NewSubscriptionService(queries, festivalSvc, emailSvc, cfg.ConfirmationTTL)
+    previewSvc := service.NewPreviewService(queries, festivalSvc, emailSvc, cfg.ConfirmationTTL)
+    calendarSvc := service.NewCalendarService(queries, festivalSvc, cfg.BaseURL)
+    searchSvc := service.NewSearchService(queries)
+
+    mediaStore, err := storage.New(storage.Config{
+        Backend:           cfg.MediaStorage,
+        PublicURL:         cfg.MediaPublicURL,
+        BaseURL:           cfg.BaseURL,
+        LocalDir:          cfg.MediaDir,
+        S3Endpoint:        cfg.S3Endpoint,
+        S3Bucket:          cfg.S3Bucket,
+        S3Region:          cfg.S3Region,
+        S3AccessKeyID:     cfg.S3AccessKeyID,
+        S3SecretAccessKey: cfg.S3SecretAccessKey,
+        S3UseSSL:          cfg.S3UseSSL,
+    })
+    if err != nil {
+        log.Fatal("failed to configure media storage:", err)
+    }
+    mediaSvc := service.NewMediaService(pool, queries, mediaStore, int64(cfg.MediaMaxUploadBytes))
+
+    h := handler.New(pool, handler.Services{
+        Festivals:     festivalSvc,
+        Memories:      memorySvc,
+        Subscriptions: subscriptionSvc,
+        Previews:      previewSvc,
+        Calendars:     calendarSvc,
+        Search:        searchSvc,
+        Media:         mediaSvc,
+        Email:         emailSvc,
+    })
+
+    // background jobs
+    if cfg.SchedulerEnabled {
+        digestSvc := service.NewDigestService(queries, emailSvc)
+        reminderSvc := service.NewReminderService(queries, emailSvc, loc)
+
+        sched := scheduler.New(queries, 5*time.Minute)
+        sched.Register(scheduler.Job{
+            Name:   "weekly-digest",
+            Period: scheduler.Weekly(time.Monday, 8, loc),
+            Run:    digestSvc.SendWeekly,
+        })
+        sched.Register(scheduler.Job{
+            Name:   "festival-reminders",
+            Period: scheduler.Daily(9, loc),
+            Run:    reminderSvc.SendDue,
+        })
+        sched.Register(scheduler.Job{
+            Name:   "expired-subscriptions-cleanup",
+            Period: scheduler.Daily(3, loc),
+            Run:    subscriptionSvc.DeleteExpiredUnconfirmed,
+        })
+        sched.Register(scheduler.Job{
+            Name:   "festival-date-generation",
+            Period: scheduler.Daily(2, loc),
+            Run:    festivalSvc.GenerateDates,
+        })
+        sched.Start(ctx)
+    }
+
+    e := echo.New()
+    e.HideBanner = true
+
+    // global middleware
+    e.Use(echomw.Logger())
+    e.Use(echomw.Recover())
+    e.Use(echomw.CORSWithConfig(echomw.CORSConfig{
+        AllowOrigins:     strings.Split(cfg.AllowedOrigins, ","),
+        AllowMethods:     []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions},
+        AllowHeaders:     []string{echo.HeaderContentType, "X-API-Key", "If-Match"},
+        ExposeHeaders:    []string{"ETag"},
+        AllowCredentials: true,
+    }))
+
+    // rate limiters
+    memoryRateLimiter := middleware.NewRateLimiter(5, time.Hour)     // 5/hour for memories
+    subscribeRateLimiter := middleware.NewRateLimiter(10, time.Hour) // 10/hour for subscribe
+
+    // health check
+    e.GET("/health", h.Health)
+
+    // uploaded media, when stored locally rather than in a bucket
+    if local, ok := mediaStore.(*storage.Local); ok {
+        e.GET(local.MountPath()+"/*", echo.WrapHandler(http.StripPrefix(local.MountPath(), local.Handler())))
+    }
+
+    // public api routes
+    api := e.Group("/api")
+
+    // festivals (public)
+    api.GET("/festivals", h.ListFestivals)
+    api.GET("/festivals/upcoming", h.ListUpcomingFestivals)
+    api.GET("/festivals/calendar", h.ListFestivalsByYear)
+    api.GET("/festivals/calendar.ics", h.FestivalsCalendarFeed)
+    api.GET("/festivals/:slug", h.GetFestival)
+    api.GET("/festivals/:slug/dates", h.GetFestivalDates)
+    api.GET("/festivals/:slug/calendar.ics", h.FestivalCalendarFeed)
+    api.GET("/festivals/:slug/memories", h.ListMemoriesByFestival)
+
+    // search (public)
+    api.GET("/search", h.Search)
+
+    // memories (public, rate limited)
+    api.POST("/memories", h.CreateMemory, memoryRateLimiter.Middleware())
+    api.GET("/memories/unsubscribe/:token", h.OptOutMemoryAuthor)
+    api.POST("/memories/unsubscribe/:token", h.OptOutMemoryAuthor) // RFC 8058 one-click
+
+    // subscriptions (public)
+    api.POST("/subscribe", h.Subscribe, subscribeRateLimiter.Middleware())
+    api.GET("/subscribe/confirm/:token", h.ConfirmSubscription)
+    api.GET("/unsubscribe/:token", h.Unsubscribe)
+    api.POST("/unsubscribe/:token", h.Unsubscribe) // RFC 8058 one-click
+    api.GET("/subscriptions/:token/reminders", h.GetReminders)
+    api.PUT("/subscriptions/:token/reminders", h.UpdateReminders)
+    api.GET("/subscriptions/:token/calendar", h.GetCalendarURL)
+    api.POST("/subscriptions/:token/calendar/rotate", h.RotateCalendarToken)
+    api.GET("/calendar/:token", h.PersonalCalendarFeed)
+
+    // admin routes (protected)
+    admin := api.Group("/admin", middleware.APIKeyAuth(cfg.AdminKeys))
+
+    // admin: memories
+    admin.GET("/memories", h.ListAllMemories)
+    admin.GET("/memories/queue", h.MemoryQueue)
+    admin.POST("/memories/review", h.BulkReviewMemories)
+    admin.GET("/memories/:id/reviews", h.ListMemoryReviews)
+    admin.PATCH("/memories/:id", h.UpdateMemoryStatus)
+    admin.DELETE("/memories/:id", h.DeleteMemory)
+
+    // admin: subscriptions
+    admin.GET("/subscriptions", h.ListAllSubscriptions)
+    admin.DELETE("/subscriptions/:id", h.DeleteSubscription)
+
+    // admin: festivals
+    admin.POST("/festivals", h.CreateFestival)
+    admin.GET("/festivals/:id", h.GetAdminFestival)
+    admin.PUT("/festivals/:id", h.UpdateFestival)
+    admin.PATCH("/festivals/:id", h.PatchFestival)
+    admin.DELETE("/festivals/:id", h.DeleteFestival)
+    admin.PUT("/festivals/:id/recurrence", h.SetFestivalRecurrence)
+    admin.POST("/festivals/:id/gallery", h.AddGalleryImage)
+    admin.PUT("/festivals/:id/gallery/order", h.ReorderGallery)
+    admin.DELETE("/festivals/:id/gallery/:item_id", h.RemoveGalleryImage)
+    admin.POST("/festivals/:id/videos", h.AddVideoEmbed)
+    admin.PUT("/festivals/:id/videos/order", h.ReorderVideos)
+    admin.DELETE("/festivals/:id/videos/:item_id", h.RemoveVideoEmbed)
+
+    // admin: media
+    admin.POST("/media", h.UploadMedia)
+    admin.GET("/media/:id", h.GetMedia)
+    admin.DELETE("/media/:id", h.DeleteMedia)
+
+    // admin: festival dates
+    admin.POST("/festival-dates", h.CreateFestivalDate)
+    admin.PUT("/festival-dates/:id", h.UpdateFestivalDate)
+    admin.DELETE("/festival-dates/:id", h.DeleteFestivalDate)
+    admin.GET("/festival-dates/easter", h.GetEasterDates)
+    admin.POST("/festival-dates/easter", h.PrefillEasterDates)
+    admin.GET("/festival-dates/lunar", h.GetLunarEstimates)
+    admin.GET("/festival-dates/tentative", h.ListTentativeFestivalDates)
+    admin.POST("/festival-dates/:id/confirm", h.ConfirmFestivalDate)
+
+    // admin: test emails
+    admin.POST("/test-email/welcome", h.TestWelcomeEmail)
+    admin.POST("/test-email/reminder", h.TestFestivalReminder)
+    admin.POST("/test-email/digest", h.TestWeeklyDigest)
+
+    // admin: email previews (rendered, never sent)
+    admin.GET("/email-preview/:type", h.PreviewEmail)
+
+    log.Printf("server starting on port %s", cfg.Port)
+    e.Logger.Fatal(e.Start(":" + cfg.Port))
+}
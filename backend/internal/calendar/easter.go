@@ -0,0 +1,41 @@
+package calendar
+
+import "time"
+
+// Easter returns Western Easter Sunday for year, using the anonymous
+// Gregorian computus (Meeus/Jones/Butcher).
+func Easter(year int) time.Time {
+    a := year % 19
+    b := year / 100
+    c := year % 100
+    d := b / 4
+    e := b % 4
+    f := (b + 8) / 25
+    g := (b - f + 1) / 3
+    h := (19*a + b - d - g + 15) % 30
+    i := c / 4
+    k := c % 4
+    l := (32 + 2*e + 2*i - h - k) % 7
+    m := (a + 11*h + 22*l) / 451
+    month := (h + l - 7*m + 114) / 31
+    day := (h+l-7*m+114)%31 + 1
+
+    return date(year, time.Month(month), day)
+}
+
+// EasterOffset is a day observed a fixed number of days from Easter Sunday.
+type EasterOffset struct {
+    Name       string
+    OffsetDays int
+}
+
+// EasterOffsets are the movable observances in Trinidad and Tobago.
+var EasterOffsets = []EasterOffset{
+    {Name: "Carnival Monday", OffsetDays: -48},
+    {Name: "Carnival Tuesday", OffsetDays: -47},
+    {Name: "Ash Wednesday", OffsetDays: -46},
+    {Name: "Good Friday", OffsetDays: -2},
+    {Name: "Easter Sunday", OffsetDays: 0},
+    {Name: "Easter Monday", OffsetDays: 1},
+    {Name: "Corpus Christi", OffsetDays: 60},
+}
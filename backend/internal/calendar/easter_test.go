@@ -0,0 +1,71 @@
+package calendar
+
+import (
+    "testing"
+    "time"
+)
+
+func TestEaster(t *testing.T) {
+    tests := []struct {
+        year int
+        want time.Time
+    }{
+        {2000, date(2000, time.April, 23)},
+        {2019, date(2019, time.April, 21)},
+        {2024, date(2024, time.March, 31)},
+        {2025, date(2025, time.April, 20)},
+        {2026, date(2026, time.April, 5)},
+    }
+
+    for _, tt := range tests {
+        if got := Easter(tt.year); !got.Equal(tt.want) {
+            t.Errorf("Easter(%d) = %s, want %s", tt.year, got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+        }
+    }
+}
+
+func TestEasterRuleDates(t *testing.T) {
+    tests := []struct {
+        name      string
+        rule      Rule
+        year      int
+        wantStart time.Time
+        wantEnd   time.Time
+    }{
+        {
+            name:      "carnival",
+            rule:      Rule{Type: RuleEaster, OffsetDays: -48, DurationDays: 2},
+            year:      2025,
+            wantStart: date(2025, time.March, 3),
+            wantEnd:   date(2025, time.March, 4),
+        },
+        {
+            name:      "good friday",
+            rule:      Rule{Type: RuleEaster, OffsetDays: -2},
+            year:      2024,
+            wantStart: date(2024, time.March, 29),
+            wantEnd:   date(2024, time.March, 29),
+        },
+        {
+            name:      "corpus christi",
+            rule:      Rule{Type: RuleEaster, OffsetDays: 60},
+            year:      2025,
+            wantStart: date(2025, time.June, 19),
+            wantEnd:   date(2025, time.June, 19),
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            start, end, err := tt.rule.Dates(tt.year)
+            if err != nil {
+                t.Fatalf("Dates(%d): %v", tt.year, err)
+            }
+            if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+                t.Errorf("Dates(%d) = %s to %s, want %s to %s", tt.year,
+                    start.Format(time.DateOnly), end.Format(time.DateOnly),
+                    tt.wantStart.Format(time.DateOnly), tt.wantEnd.Format(time.DateOnly))
+            }
+        })
+    }
+}
@@ -0,0 +1,51 @@
+package calendar
+
+import (
+    "errors"
+    "time"
+)
+
+// jdnUnixEpoch is the Julian Day Number of 1970-01-01.
+const jdnUnixEpoch = 2440588
+
+// Hijri is the tabular (arithmetical) Islamic calendar. Observed dates
+// follow moon sightings and often land a day after the tabular date.
+type Hijri struct{}
+
+func (Hijri) Validate(month, day int) error {
+    if month < 1 || month > 12 {
+        return errors.New("hijri month must be between 1 and 12")
+    }
+    if day < 1 || day > 30 {
+        return errors.New("hijri day must be between 1 and 30")
+    }
+
+    return nil
+}
+
+// Estimate returns the first date in the Gregorian year that is month/day in
+// the Hijri calendar. A Hijri year is 11 days shorter, so about once every 33
+// years a date occurs twice in one Gregorian year.
+func (h Hijri) Estimate(year, month, day int) (time.Time, error) {
+    if err := h.Validate(month, day); err != nil {
+        return time.Time{}, err
+    }
+
+    approx := (year - 622) * 33 / 32
+    for hy := approx - 1; hy <= approx+2; hy++ {
+        d := fromJDN(hijriToJDN(hy, month, day))
+        if d.Year() == year {
+            return d, nil
+        }
+    }
+
+    return time.Time{}, ErrNoEstimate
+}
+
+func hijriToJDN(year, month, day int) int {
+    return day + (59*(month-1)+1)/2 + (year-1)*354 + (3+11*year)/30 + 1948439
+}
+
+func fromJDN(jdn int) time.Time {
+    return date(1970, time.January, 1).AddDate(0, 0, jdn-jdnUnixEpoch)
+}
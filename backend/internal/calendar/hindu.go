@@ -0,0 +1,182 @@
+package calendar
+
+import (
+    "errors"
+    "math"
+    "time"
+)
+
+const (
+    // HinduFullMoon is the Purnima tithi, the 15th day of a lunar month.
+    HinduFullMoon = 15
+    // HinduNewMoon is the Amavasya tithi that ends a lunar month.
+    HinduNewMoon = 30
+
+    hinduMonths  = 12
+    lunationDays = 29.530588861
+
+    // sunset in Trinidad stays close to 18:00 all year, which is close
+    // enough for tithi estimates
+    sunsetHour = 18
+)
+
+// Trinidad doesn't observe daylight saving.
+var trinidad = time.FixedZone("AST", -4*60*60)
+
+// Hindu is the amanta lunisolar calendar as used for Trinidad's Hindu
+// festivals. Months run new moon to new moon, 1 is Chaitra and 12 is
+// Phalguna, and only the Purnima (15) and Amavasya (30) days are supported.
+// A festival falls on the day whose sunset is within the tithi.
+type Hindu struct{}
+
+func (Hindu) Validate(month, day int) error {
+    if month < 1 || month > hinduMonths {
+        return errors.New("hindu month must be between 1 (Chaitra) and 12 (Phalguna)")
+    }
+    if day != HinduFullMoon && day != HinduNewMoon {
+        return errors.New("hindu day must be 15 (Purnima) or 30 (Amavasya)")
+    }
+
+    return nil
+}
+
+func (h Hindu) Estimate(year, month, day int) (time.Time, error) {
+    if err := h.Validate(month, day); err != nil {
+        return time.Time{}, err
+    }
+
+    // new moons from a little before the year until a little after it
+    k0 := math.Floor(float64(year-2000)*12.3685) - 2
+    newMoons := make([]time.Time, 0, 17)
+    for k := k0; k < k0+17; k++ {
+        newMoons = append(newMoons, moonPhase(k, false))
+    }
+
+    for i := 0; i+1 < len(newMoons); i++ {
+        sign := siderealSign(newMoons[i])
+        if sign == siderealSign(newMoons[i+1]) {
+            continue // adhika month, the festival is kept in the month after
+        }
+
+        // a month is named after the sign the sun enters during it
+        if (sign+2)%12 != month%12 {
+            continue
+        }
+
+        var event time.Time
+        if day == HinduFullMoon {
+            event = moonPhase(k0+float64(i), true)
+        } else {
+            event = newMoons[i+1]
+        }
+
+        d := tithiDate(event)
+        if d.Year() == year {
+            return d, nil
+        }
+    }
+
+    return time.Time{}, ErrNoEstimate
+}
+
+// tithiDate returns the day whose sunset falls in the tithi ending at event.
+// A tithi lasts roughly a day, so that's the day of the event when it comes
+// after sunset, otherwise the day before.
+func tithiDate(event time.Time) time.Time {
+    local := event.In(trinidad)
+    d := date(local.Year(), local.Month(), local.Day())
+    if local.Hour() < sunsetHour {
+        d = d.AddDate(0, 0, -1)
+    }
+
+    return d
+}
+
+// siderealSign returns the zodiac sign (0 is Mesha/Aries) the sun is in at
+// t, using the Lahiri ayanamsa.
+func siderealSign(t time.Time) int {
+    jd := julianDay(t)
+    years := (jd - 2451545.0) / 365.25
+    ayanamsa := 23.853 + years*0.0139694
+
+    lon := math.Mod(sunLongitude(jd)-ayanamsa+360, 360)
+
+    return int(lon / 30)
+}
+
+// sunLongitude is the sun's apparent tropical longitude in degrees (Meeus,
+// Astronomical Algorithms, ch. 25, low accuracy).
+func sunLongitude(jd float64) float64 {
+    t := (jd - 2451545.0) / 36525
+    l0 := 280.46646 + 36000.76983*t + 0.0003032*t*t
+    m := rad(357.52911 + 35999.05029*t - 0.0001537*t*t)
+    c := (1.914602-0.004817*t-0.000014*t*t)*math.Sin(m) +
+        (0.019993-0.000101*t)*math.Sin(2*m) +
+        0.000289*math.Sin(3*m)
+    omega := rad(125.04 - 1934.136*t)
+
+    return math.Mod(l0+c-0.00569-0.00478*math.Sin(omega), 360)
+}
+
+// moonPhase returns the new moon for lunation k (0 is January 2000), or the
+// following full moon (Meeus, Astronomical Algorithms, ch. 49).
+func moonPhase(k float64, full bool) time.Time {
+    if full {
+        k += 0.5
+    }
+
+    t := k / 1236.85
+    jde := 2451550.09766 + lunationDays*k + 0.00015437*t*t - 0.000000150*t*t*t + 0.00000000073*t*t*t*t
+
+    e := 1 - 0.002516*t - 0.0000074*t*t
+    m := rad(2.5534 + 29.10535670*k - 0.0000014*t*t - 0.00000011*t*t*t)
+    mp := rad(201.5643 + 385.81693528*k + 0.0107582*t*t + 0.00001238*t*t*t - 0.000000058*t*t*t*t)
+    f := rad(160.7108 + 390.67050284*k - 0.0016118*t*t - 0.00000227*t*t*t + 0.000000011*t*t*t*t)
+    omega := rad(124.7746 - 1.56375588*k + 0.0020672*t*t + 0.00000215*t*t*t)
+
+    // the leading terms differ between new and full moon
+    c := [...]float64{-0.40720, 0.17241, 0.01608, 0.01039, 0.00739, -0.00514, 0.00208}
+    if full {
+        c = [...]float64{-0.40614, 0.17302, 0.01614, 0.01043, 0.00734, -0.00515, 0.00209}
+    }
+
+    jde += c[0]*math.Sin(mp) +
+        c[1]*e*math.Sin(m) +
+        c[2]*math.Sin(2*mp) +
+        c[3]*math.Sin(2*f) +
+        c[4]*e*math.Sin(mp-m) +
+        c[5]*e*math.Sin(mp+m) +
+        c[6]*e*e*math.Sin(2*m) -
+        0.00111*math.Sin(mp-2*f) -
+        0.00057*math.Sin(mp+2*f) +
+        0.00056*e*math.Sin(2*mp+m) -
+        0.00042*math.Sin(3*mp) +
+        0.00042*e*math.Sin(m+2*f) +
+        0.00038*e*math.Sin(m-2*f) -
+        0.00024*e*math.Sin(2*mp-m) -
+        0.00017*math.Sin(omega) -
+        0.00007*math.Sin(mp+2*m) +
+        0.00004*math.Sin(2*mp-2*f) +
+        0.00004*math.Sin(3*m) +
+        0.00003*math.Sin(mp+m-2*f) +
+        0.00003*math.Sin(2*mp+2*f) -
+        0.00003*math.Sin(mp+m+2*f) +
+        0.00003*math.Sin(mp-m+2*f) -
+        0.00002*math.Sin(mp-m-2*f) -
+        0.00002*math.Sin(3*mp+m) +
+        0.00002*math.Sin(4*mp)
+
+    return fromJulianDay(jde)
+}
+
+func julianDay(t time.Time) float64 {
+    return float64(t.Unix())/86400 + 2440587.5
+}
+
+func fromJulianDay(jd float64) time.Time {
+    return time.Unix(int64(math.Round((jd-2440587.5)*86400)), 0).UTC()
+}
+
+func rad(deg float64) float64 {
+    return deg * math.Pi / 180
+}
@@ -0,0 +1,38 @@
+package calendar
+
+import "time"
+
+const (
+    CalendarHijri = "hijri"
+    CalendarHindu = "hindu"
+)
+
+// LunarCalendar estimates the Gregorian date of a day in a lunar or
+// lunisolar calendar. Estimates can be off by a day or two since the real
+// dates depend on moon sightings and local announcements.
+type LunarCalendar interface {
+    Validate(month, day int) error
+    Estimate(year, month, day int) (time.Time, error)
+}
+
+// lunarCalendars lists the calendars lunar rules may use. A nil entry has no
+// estimator, so its dates have to be entered by hand.
+var lunarCalendars = map[string]LunarCalendar{
+    CalendarHijri: Hijri{},
+    CalendarHindu: Hindu{},
+}
+
+// LunarFestival is a festival whose date follows a lunar calendar.
+type LunarFestival struct {
+    Name string
+    Rule Rule
+}
+
+// LunarFestivals are the lunar and lunisolar festivals observed in Trinidad
+// and Tobago.
+var LunarFestivals = []LunarFestival{
+    {Name: "Divali", Rule: Rule{Type: RuleLunar, Calendar: CalendarHindu, Month: 7, Day: HinduNewMoon}},
+    {Name: "Phagwa", Rule: Rule{Type: RuleLunar, Calendar: CalendarHindu, Month: 12, Day: HinduFullMoon, OffsetDays: 1}},
+    {Name: "Eid-ul-Fitr", Rule: Rule{Type: RuleLunar, Calendar: CalendarHijri, Month: 10, Day: 1}},
+    {Name: "Hosay", Rule: Rule{Type: RuleLunar, Calendar: CalendarHijri, Month: 1, Day: 7, DurationDays: 4}},
+}
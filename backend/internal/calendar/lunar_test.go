@@ -0,0 +1,71 @@
+package calendar
+
+import (
+    "testing"
+    "time"
+)
+
+// The estimates are pinned to the dates the festivals were observed on in
+// Trinidad and Tobago, so a change to the approximations that moves any of
+// them by a day shows up here.
+func TestLunarFestivalDates(t *testing.T) {
+    rules := make(map[string]Rule, len(LunarFestivals))
+    for _, f := range LunarFestivals {
+        rules[f.Name] = f.Rule
+    }
+
+    tests := []struct {
+        festival  string
+        year      int
+        wantStart time.Time
+        wantEnd   time.Time
+    }{
+        {"Divali", 2023, date(2023, time.November, 12), date(2023, time.November, 12)},
+        {"Divali", 2024, date(2024, time.October, 31), date(2024, time.October, 31)},
+        {"Divali", 2025, date(2025, time.October, 20), date(2025, time.October, 20)},
+        {"Phagwa", 2024, date(2024, time.March, 25), date(2024, time.March, 25)},
+        {"Phagwa", 2025, date(2025, time.March, 14), date(2025, time.March, 14)},
+        {"Eid-ul-Fitr", 2024, date(2024, time.April, 10), date(2024, time.April, 10)},
+        {"Eid-ul-Fitr", 2025, date(2025, time.March, 31), date(2025, time.March, 31)},
+        {"Hosay", 2023, date(2023, time.July, 25), date(2023, time.July, 28)},
+        {"Hosay", 2024, date(2024, time.July, 14), date(2024, time.July, 17)},
+        {"Hosay", 2025, date(2025, time.July, 3), date(2025, time.July, 6)},
+    }
+
+    for _, tt := range tests {
+        start, end, err := rules[tt.festival].Dates(tt.year)
+        if err != nil {
+            t.Errorf("%s %d: %v", tt.festival, tt.year, err)
+            continue
+        }
+        if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+            t.Errorf("%s %d = %s to %s, want %s to %s", tt.festival, tt.year,
+                start.Format(time.DateOnly), end.Format(time.DateOnly),
+                tt.wantStart.Format(time.DateOnly), tt.wantEnd.Format(time.DateOnly))
+        }
+    }
+}
+
+func TestLunarValidate(t *testing.T) {
+    tests := []struct {
+        name  string
+        cal   LunarCalendar
+        month int
+        day   int
+        ok    bool
+    }{
+        {"hijri shawwal 1", Hijri{}, 10, 1, true},
+        {"hijri month 13", Hijri{}, 13, 1, false},
+        {"hijri day 31", Hijri{}, 1, 31, false},
+        {"hindu purnima", Hindu{}, 12, HinduFullMoon, true},
+        {"hindu amavasya", Hindu{}, 7, HinduNewMoon, true},
+        {"hindu other tithi", Hindu{}, 7, 10, false},
+    }
+
+    for _, tt := range tests {
+        err := tt.cal.Validate(tt.month, tt.day)
+        if (err == nil) != tt.ok {
+            t.Errorf("%s: Validate(%d, %d) = %v, want ok %t", tt.name, tt.month, tt.day, err, tt.ok)
+        }
+    }
+}
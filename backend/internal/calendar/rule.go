@@ -0,0 +1,175 @@
+// Package calendar computes festival dates from recurrence rules.
+package calendar
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+    "time"
+)
+
+const (
+    RuleFixed      = "fixed"
+    RuleNthWeekday = "nth_weekday"
+    RuleEaster     = "easter"
+    RuleLunar      = "lunar"
+)
+
+var (
+    ErrInvalidRule = errors.New("invalid recurrence rule")
+    // ErrNoEstimate means the rule can't produce a date for the year, e.g. a
+    // lunar rule whose calendar has no estimator. Dates for those years are
+    // entered by hand.
+    ErrNoEstimate = errors.New("no date estimate available")
+)
+
+var weekdays = map[string]time.Weekday{
+    "sunday":    time.Sunday,
+    "monday":    time.Monday,
+    "tuesday":   time.Tuesday,
+    "wednesday": time.Wednesday,
+    "thursday":  time.Thursday,
+    "friday":    time.Friday,
+    "saturday":  time.Saturday,
+}
+
+// Rule describes when a festival happens each year. Which fields are used
+// depends on Type:
+//
+//   - fixed: Month and Day, e.g. Emancipation Day on August 1
+//   - nth_weekday: Nth Weekday of Month, Nth -1 is the last one
+//   - easter: OffsetDays from Easter Sunday, e.g. -48 for Carnival Monday
+//   - lunar: Month and Day in Calendar ("hijri" or "hindu"), moved by
+//     OffsetDays, e.g. Phagwa is the day after Phalguna Purnima
+//
+// DurationDays is how many days the festival lasts (default 1).
+type Rule struct {
+    Type         string `json:"type"`
+    Month        int    `json:"month,omitempty"`
+    Day          int    `json:"day,omitempty"`
+    Weekday      string `json:"weekday,omitempty"`
+    Nth          int    `json:"nth,omitempty"`
+    OffsetDays   int    `json:"offset_days,omitempty"`
+    Calendar     string `json:"calendar,omitempty"`
+    DurationDays int    `json:"duration_days,omitempty"`
+}
+
+func (r Rule) Validate() error {
+    if r.DurationDays < 0 || r.DurationDays > 31 {
+        return fmt.Errorf("%w: duration_days must be between 1 and 31", ErrInvalidRule)
+    }
+
+    switch r.Type {
+    case RuleFixed:
+        if r.Month < 1 || r.Month > 12 {
+            return fmt.Errorf("%w: month must be between 1 and 12", ErrInvalidRule)
+        }
+        // 2024 is a leap year, so February 29 is allowed
+        if r.Day < 1 || r.Day > daysIn(2024, time.Month(r.Month)) {
+            return fmt.Errorf("%w: day %d is not in month %d", ErrInvalidRule, r.Day, r.Month)
+        }
+
+    case RuleNthWeekday:
+        if r.Month < 1 || r.Month > 12 {
+            return fmt.Errorf("%w: month must be between 1 and 12", ErrInvalidRule)
+        }
+        if _, ok := weekdays[strings.ToLower(r.Weekday)]; !ok {
+            return fmt.Errorf("%w: unknown weekday %q", ErrInvalidRule, r.Weekday)
+        }
+        if r.Nth == 0 || r.Nth < -1 || r.Nth > 5 {
+            return fmt.Errorf("%w: nth must be 1-5, or -1 for the last", ErrInvalidRule)
+        }
+
+    case RuleEaster:
+        if r.OffsetDays < -100 || r.OffsetDays > 100 {
+            return fmt.Errorf("%w: offset_days must be between -100 and 100", ErrInvalidRule)
+        }
+
+    case RuleLunar:
+        if r.OffsetDays < -30 || r.OffsetDays > 30 {
+            return fmt.Errorf("%w: offset_days must be between -30 and 30", ErrInvalidRule)
+        }
+
+        cal, ok := lunarCalendars[r.Calendar]
+        if !ok {
+            return fmt.Errorf("%w: unknown calendar %q", ErrInvalidRule, r.Calendar)
+        }
+        if cal == nil {
+            return nil // no estimator to check against yet
+        }
+        if err := cal.Validate(r.Month, r.Day); err != nil {
+            return fmt.Errorf("%w: %v", ErrInvalidRule, err)
+        }
+
+    default:
+        return fmt.Errorf("%w: unknown type %q", ErrInvalidRule, r.Type)
+    }
+
+    return nil
+}
+
+// Dates returns the first and last day of the festival in year. Dates are
+// at midnight UTC, matching how the database stores DATE columns.
+func (r Rule) Dates(year int) (start, end time.Time, err error) {
+    if err := r.Validate(); err != nil {
+        return time.Time{}, time.Time{}, err
+    }
+
+    switch r.Type {
+    case RuleFixed:
+        start = date(year, time.Month(r.Month), r.Day)
+        if start.Month() != time.Month(r.Month) {
+            // February 29 outside a leap year
+            return time.Time{}, time.Time{}, ErrNoEstimate
+        }
+
+    case RuleNthWeekday:
+        start = nthWeekday(year, time.Month(r.Month), weekdays[strings.ToLower(r.Weekday)], r.Nth)
+        if start.Month() != time.Month(r.Month) {
+            // no fifth weekday this month
+            return time.Time{}, time.Time{}, ErrNoEstimate
+        }
+
+    case RuleEaster:
+        start = Easter(year).AddDate(0, 0, r.OffsetDays)
+
+    case RuleLunar:
+        cal := lunarCalendars[r.Calendar]
+        if cal == nil {
+            return time.Time{}, time.Time{}, ErrNoEstimate
+        }
+
+        start, err = cal.Estimate(year, r.Month, r.Day)
+        if err != nil {
+            return time.Time{}, time.Time{}, err
+        }
+        start = start.AddDate(0, 0, r.OffsetDays)
+    }
+
+    days := r.DurationDays
+    if days == 0 {
+        days = 1
+    }
+
+    return start, start.AddDate(0, 0, days-1), nil
+}
+
+func date(year int, month time.Month, day int) time.Time {
+    return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func daysIn(year int, month time.Month) int {
+    return date(year, month+1, 0).Day()
+}
+
+func nthWeekday(year int, month time.Month, weekday time.Weekday, nth int) time.Time {
+    if nth < 0 {
+        last := date(year, month, daysIn(year, month))
+        return last.AddDate(0, 0, -int((last.Weekday()-weekday+7)%7))
+    }
+
+    first := date(year, month, 1)
+    offset := int((weekday - first.Weekday() + 7) % 7)
+
+    return first.AddDate(0, 0, offset+7*(nth-1))
+}
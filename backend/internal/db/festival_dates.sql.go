@@ -16,7 +16,7 @@ INSERT INTO festival_dates (
     festival_id, year, start_date, end_date, is_tentative
 ) VALUES (
     $1, $2, $3, $4, $5
-) RETURNING id, festival_id, year, start_date, end_date, is_tentative, created_at
+) RETURNING id, festival_id, year, start_date, end_date, is_tentative, created_at, source
 `
 
 type CreateFestivalDateParams struct {
@@ -44,6 +44,7 @@ func (q *Queries) CreateFestivalDate(ctx context.Context, arg CreateFestivalDate
 		&i.EndDate,
 		&i.IsTentative,
 		&i.CreatedAt,
+		&i.Source,
 	)
 	return i, err
 }
@@ -68,8 +69,29 @@ func (q *Queries) DeleteFestivalDatesByFestivalID(ctx context.Context, festivalI
 	return err
 }
 
+const getFestivalDateByID = `-- name: GetFestivalDateByID :one
+SELECT id, festival_id, year, start_date, end_date, is_tentative, created_at, source FROM festival_dates
+WHERE id = $1
+`
+
+func (q *Queries) GetFestivalDateByID(ctx context.Context, id pgtype.UUID) (FestivalDate, error) {
+	row := q.db.QueryRow(ctx, getFestivalDateByID, id)
+	var i FestivalDate
+	err := row.Scan(
+		&i.ID,
+		&i.FestivalID,
+		&i.Year,
+		&i.StartDate,
+		&i.EndDate,
+		&i.IsTentative,
+		&i.CreatedAt,
+		&i.Source,
+	)
+	return i, err
+}
+
 const getFestivalDateByYear = `-- name: GetFestivalDateByYear :one
-SELECT id, festival_id, year, start_date, end_date, is_tentative, created_at FROM festival_dates
+SELECT id, festival_id, year, start_date, end_date, is_tentative, created_at, source FROM festival_dates
 WHERE festival_id = $1 AND year = $2
 `
 
@@ -89,12 +111,13 @@ func (q *Queries) GetFestivalDateByYear(ctx context.Context, arg GetFestivalDate
 		&i.EndDate,
 		&i.IsTentative,
 		&i.CreatedAt,
+		&i.Source,
 	)
 	return i, err
 }
 
 const getFestivalDatesByFestivalID = `-- name: GetFestivalDatesByFestivalID :many
-SELECT id, festival_id, year, start_date, end_date, is_tentative, created_at FROM festival_dates
+SELECT id, festival_id, year, start_date, end_date, is_tentative, created_at, source FROM festival_dates
 WHERE festival_id = $1
 ORDER BY year DESC
 `
@@ -116,6 +139,66 @@ func (q *Queries) GetFestivalDatesByFestivalID(ctx context.Context, festivalID p
 			&i.EndDate,
 			&i.IsTentative,
 			&i.CreatedAt,
+			&i.Source,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFestivalDatesBetween = `-- name: ListFestivalDatesBetween :many
+SELECT fd.id, fd.festival_id, fd.year, fd.start_date, fd.end_date, fd.is_tentative, fd.created_at, fd.source, f.slug, f.name
+FROM festival_dates fd
+JOIN festivals f ON f.id = fd.festival_id
+WHERE f.is_published = true
+  AND fd.start_date >= $1::date
+  AND fd.start_date <= $2::date
+ORDER BY fd.start_date ASC
+`
+
+type ListFestivalDatesBetweenParams struct {
+	FromDate pgtype.Date `json:"fromDate"`
+	ToDate   pgtype.Date `json:"toDate"`
+}
+
+type ListFestivalDatesBetweenRow struct {
+	ID          pgtype.UUID        `json:"id"`
+	FestivalID  pgtype.UUID        `json:"festivalId"`
+	Year        int32              `json:"year"`
+	StartDate   pgtype.Date        `json:"startDate"`
+	EndDate     pgtype.Date        `json:"endDate"`
+	IsTentative pgtype.Bool        `json:"isTentative"`
+	CreatedAt   pgtype.Timestamptz `json:"createdAt"`
+	Source      string             `json:"source"`
+	Slug        string             `json:"slug"`
+	Name        string             `json:"name"`
+}
+
+func (q *Queries) ListFestivalDatesBetween(ctx context.Context, arg ListFestivalDatesBetweenParams) ([]ListFestivalDatesBetweenRow, error) {
+	rows, err := q.db.Query(ctx, listFestivalDatesBetween, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListFestivalDatesBetweenRow{}
+	for rows.Next() {
+		var i ListFestivalDatesBetweenRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FestivalID,
+			&i.Year,
+			&i.StartDate,
+			&i.EndDate,
+			&i.IsTentative,
+			&i.CreatedAt,
+			&i.Source,
+			&i.Slug,
+			&i.Name,
 		); err != nil {
 			return nil, err
 		}
@@ -128,7 +211,7 @@ func (q *Queries) GetFestivalDatesByFestivalID(ctx context.Context, festivalID p
 }
 
 const listFestivalDatesByYear = `-- name: ListFestivalDatesByYear :many
-SELECT fd.id, fd.festival_id, fd.year, fd.start_date, fd.end_date, fd.is_tentative, fd.created_at, f.slug, f.name, f.region, f.heritage_type, f.festival_type, f.summary
+SELECT fd.id, fd.festival_id, fd.year, fd.start_date, fd.end_date, fd.is_tentative, fd.created_at, fd.source, f.slug, f.name, f.region, f.heritage_type, f.festival_type, f.summary
 FROM festival_dates fd
 JOIN festivals f ON f.id = fd.festival_id
 WHERE fd.year = $1 AND f.is_published = true
@@ -143,6 +226,7 @@ type ListFestivalDatesByYearRow struct {
 	EndDate      pgtype.Date        `json:"endDate"`
 	IsTentative  pgtype.Bool        `json:"isTentative"`
 	CreatedAt    pgtype.Timestamptz `json:"createdAt"`
+	Source       string             `json:"source"`
 	Slug         string             `json:"slug"`
 	Name         string             `json:"name"`
 	Region       string             `json:"region"`
@@ -168,6 +252,7 @@ func (q *Queries) ListFestivalDatesByYear(ctx context.Context, year int32) ([]Li
 			&i.EndDate,
 			&i.IsTentative,
 			&i.CreatedAt,
+			&i.Source,
 			&i.Slug,
 			&i.Name,
 			&i.Region,
@@ -185,8 +270,128 @@ func (q *Queries) ListFestivalDatesByYear(ctx context.Context, year int32) ([]Li
 	return items, nil
 }
 
+const listFestivalDatesForFeed = `-- name: ListFestivalDatesForFeed :many
+SELECT fd.id, fd.festival_id, fd.year, fd.start_date, fd.end_date, fd.is_tentative, fd.created_at, fd.source, f.slug, f.name, f.region, f.heritage_type, f.summary
+FROM festival_dates fd
+JOIN festivals f ON f.id = fd.festival_id
+WHERE f.is_published = true
+  AND fd.start_date >= $1::date
+  AND ($2::text IS NULL OR f.region = $2)
+  AND ($3::text IS NULL OR f.heritage_type = $3)
+ORDER BY fd.start_date ASC
+`
+
+type ListFestivalDatesForFeedParams struct {
+	FromDate pgtype.Date `json:"fromDate"`
+	Region   pgtype.Text `json:"region"`
+	Heritage pgtype.Text `json:"heritage"`
+}
+
+type ListFestivalDatesForFeedRow struct {
+	ID           pgtype.UUID        `json:"id"`
+	FestivalID   pgtype.UUID        `json:"festivalId"`
+	Year         int32              `json:"year"`
+	StartDate    pgtype.Date        `json:"startDate"`
+	EndDate      pgtype.Date        `json:"endDate"`
+	IsTentative  pgtype.Bool        `json:"isTentative"`
+	CreatedAt    pgtype.Timestamptz `json:"createdAt"`
+	Source       string             `json:"source"`
+	Slug         string             `json:"slug"`
+	Name         string             `json:"name"`
+	Region       string             `json:"region"`
+	HeritageType string             `json:"heritageType"`
+	Summary      string             `json:"summary"`
+}
+
+func (q *Queries) ListFestivalDatesForFeed(ctx context.Context, arg ListFestivalDatesForFeedParams) ([]ListFestivalDatesForFeedRow, error) {
+	rows, err := q.db.Query(ctx, listFestivalDatesForFeed, arg.FromDate, arg.Region, arg.Heritage)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListFestivalDatesForFeedRow{}
+	for rows.Next() {
+		var i ListFestivalDatesForFeedRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FestivalID,
+			&i.Year,
+			&i.StartDate,
+			&i.EndDate,
+			&i.IsTentative,
+			&i.CreatedAt,
+			&i.Source,
+			&i.Slug,
+			&i.Name,
+			&i.Region,
+			&i.HeritageType,
+			&i.Summary,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTentativeFestivalDates = `-- name: ListTentativeFestivalDates :many
+SELECT fd.id, fd.festival_id, fd.year, fd.start_date, fd.end_date, fd.is_tentative, fd.created_at, fd.source, f.slug, f.name
+FROM festival_dates fd
+JOIN festivals f ON f.id = fd.festival_id
+WHERE fd.is_tentative = true
+  AND fd.start_date >= CURRENT_DATE
+ORDER BY fd.start_date ASC
+`
+
+type ListTentativeFestivalDatesRow struct {
+	ID          pgtype.UUID        `json:"id"`
+	FestivalID  pgtype.UUID        `json:"festivalId"`
+	Year        int32              `json:"year"`
+	StartDate   pgtype.Date        `json:"startDate"`
+	EndDate     pgtype.Date        `json:"endDate"`
+	IsTentative pgtype.Bool        `json:"isTentative"`
+	CreatedAt   pgtype.Timestamptz `json:"createdAt"`
+	Source      string             `json:"source"`
+	Slug        string             `json:"slug"`
+	Name        string             `json:"name"`
+}
+
+func (q *Queries) ListTentativeFestivalDates(ctx context.Context) ([]ListTentativeFestivalDatesRow, error) {
+	rows, err := q.db.Query(ctx, listTentativeFestivalDates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTentativeFestivalDatesRow{}
+	for rows.Next() {
+		var i ListTentativeFestivalDatesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FestivalID,
+			&i.Year,
+			&i.StartDate,
+			&i.EndDate,
+			&i.IsTentative,
+			&i.CreatedAt,
+			&i.Source,
+			&i.Slug,
+			&i.Name,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listUpcomingFestivalDates = `-- name: ListUpcomingFestivalDates :many
-SELECT fd.id, fd.festival_id, fd.year, fd.start_date, fd.end_date, fd.is_tentative, fd.created_at, f.slug, f.name, f.region, f.heritage_type, f.festival_type, f.summary
+SELECT fd.id, fd.festival_id, fd.year, fd.start_date, fd.end_date, fd.is_tentative, fd.created_at, fd.source, f.slug, f.name, f.region, f.heritage_type, f.festival_type, f.summary
 FROM festival_dates fd
 JOIN festivals f ON f.id = fd.festival_id
 WHERE f.is_published = true
@@ -203,6 +408,7 @@ type ListUpcomingFestivalDatesRow struct {
 	EndDate      pgtype.Date        `json:"endDate"`
 	IsTentative  pgtype.Bool        `json:"isTentative"`
 	CreatedAt    pgtype.Timestamptz `json:"createdAt"`
+	Source       string             `json:"source"`
 	Slug         string             `json:"slug"`
 	Name         string             `json:"name"`
 	Region       string             `json:"region"`
@@ -228,6 +434,7 @@ func (q *Queries) ListUpcomingFestivalDates(ctx context.Context) ([]ListUpcoming
 			&i.EndDate,
 			&i.IsTentative,
 			&i.CreatedAt,
+			&i.Source,
 			&i.Slug,
 			&i.Name,
 			&i.Region,
@@ -249,9 +456,10 @@ const updateFestivalDate = `-- name: UpdateFestivalDate :one
 UPDATE festival_dates SET
     start_date = $2,
     end_date = $3,
-    is_tentative = $4
+    is_tentative = $4,
+    source = 'manual'
 WHERE id = $1
-RETURNING id, festival_id, year, start_date, end_date, is_tentative, created_at
+RETURNING id, festival_id, year, start_date, end_date, is_tentative, created_at, source
 `
 
 type UpdateFestivalDateParams struct {
@@ -277,6 +485,72 @@ func (q *Queries) UpdateFestivalDate(ctx context.Context, arg UpdateFestivalDate
 		&i.EndDate,
 		&i.IsTentative,
 		&i.CreatedAt,
+		&i.Source,
 	)
 	return i, err
 }
+
+const upsertComputedFestivalDate = `-- name: UpsertComputedFestivalDate :execrows
+INSERT INTO festival_dates (
+    festival_id, year, start_date, end_date, is_tentative, source
+) VALUES (
+    $1, $2, $3, $4, false, 'generated'
+)
+ON CONFLICT (festival_id, year) DO UPDATE SET
+    start_date = EXCLUDED.start_date,
+    end_date = EXCLUDED.end_date,
+    is_tentative = false
+WHERE festival_dates.source = 'generated'
+`
+
+type UpsertComputedFestivalDateParams struct {
+	FestivalID pgtype.UUID `json:"festivalId"`
+	Year       int32       `json:"year"`
+	StartDate  pgtype.Date `json:"startDate"`
+	EndDate    pgtype.Date `json:"endDate"`
+}
+
+func (q *Queries) UpsertComputedFestivalDate(ctx context.Context, arg UpsertComputedFestivalDateParams) (int64, error) {
+	result, err := q.db.Exec(ctx, upsertComputedFestivalDate,
+		arg.FestivalID,
+		arg.Year,
+		arg.StartDate,
+		arg.EndDate,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const upsertGeneratedFestivalDate = `-- name: UpsertGeneratedFestivalDate :execrows
+INSERT INTO festival_dates (
+    festival_id, year, start_date, end_date, is_tentative, source
+) VALUES (
+    $1, $2, $3, $4, true, 'generated'
+)
+ON CONFLICT (festival_id, year) DO UPDATE SET
+    start_date = EXCLUDED.start_date,
+    end_date = EXCLUDED.end_date
+WHERE festival_dates.source = 'generated' AND festival_dates.is_tentative = true
+`
+
+type UpsertGeneratedFestivalDateParams struct {
+	FestivalID pgtype.UUID `json:"festivalId"`
+	Year       int32       `json:"year"`
+	StartDate  pgtype.Date `json:"startDate"`
+	EndDate    pgtype.Date `json:"endDate"`
+}
+
+func (q *Queries) UpsertGeneratedFestivalDate(ctx context.Context, arg UpsertGeneratedFestivalDateParams) (int64, error) {
+	result, err := q.db.Exec(ctx, upsertGeneratedFestivalDate,
+		arg.FestivalID,
+		arg.Year,
+		arg.StartDate,
+		arg.EndDate,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
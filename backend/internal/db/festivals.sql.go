@@ -18,7 +18,7 @@ INSERT INTO festivals (
     cover_image_url, gallery_images, video_embeds, is_published
 ) VALUES (
     $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
-) RETURNING id, slug, name, date_type, region, heritage_type, festival_type, summary, story, what_to_expect, how_to_participate, practical_info, cover_image_url, gallery_images, video_embeds, is_published, created_at, usual_month, date_2026_start, date_2026_end
+) RETURNING id, slug, name, date_type, region, heritage_type, festival_type, summary, story, what_to_expect, how_to_participate, practical_info, cover_image_url, gallery_images, video_embeds, is_published, created_at, usual_month, date_2026_start, date_2026_end, recurrence, updated_at
 `
 
 type CreateFestivalParams struct {
@@ -79,6 +79,8 @@ func (q *Queries) CreateFestival(ctx context.Context, arg CreateFestivalParams)
 		&i.UsualMonth,
 		&i.Date2026Start,
 		&i.Date2026End,
+		&i.Recurrence,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
@@ -94,7 +96,7 @@ func (q *Queries) DeleteFestival(ctx context.Context, id pgtype.UUID) error {
 }
 
 const getFestivalByID = `-- name: GetFestivalByID :one
-SELECT id, slug, name, date_type, region, heritage_type, festival_type, summary, story, what_to_expect, how_to_participate, practical_info, cover_image_url, gallery_images, video_embeds, is_published, created_at, usual_month, date_2026_start, date_2026_end FROM festivals
+SELECT id, slug, name, date_type, region, heritage_type, festival_type, summary, story, what_to_expect, how_to_participate, practical_info, cover_image_url, gallery_images, video_embeds, is_published, created_at, usual_month, date_2026_start, date_2026_end, recurrence, updated_at FROM festivals
 WHERE id = $1
 `
 
@@ -122,12 +124,14 @@ func (q *Queries) GetFestivalByID(ctx context.Context, id pgtype.UUID) (Festival
 		&i.UsualMonth,
 		&i.Date2026Start,
 		&i.Date2026End,
+		&i.Recurrence,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
 const getFestivalBySlug = `-- name: GetFestivalBySlug :one
-SELECT id, slug, name, date_type, region, heritage_type, festival_type, summary, story, what_to_expect, how_to_participate, practical_info, cover_image_url, gallery_images, video_embeds, is_published, created_at, usual_month, date_2026_start, date_2026_end FROM festivals
+SELECT id, slug, name, date_type, region, heritage_type, festival_type, summary, story, what_to_expect, how_to_participate, practical_info, cover_image_url, gallery_images, video_embeds, is_published, created_at, usual_month, date_2026_start, date_2026_end, recurrence, updated_at FROM festivals
 WHERE slug = $1 AND is_published = true
 `
 
@@ -155,112 +159,56 @@ func (q *Queries) GetFestivalBySlug(ctx context.Context, slug string) (Festival,
 		&i.UsualMonth,
 		&i.Date2026Start,
 		&i.Date2026End,
+		&i.Recurrence,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const listFestivals = `-- name: ListFestivals :many
-SELECT id, slug, name, date_type, region, heritage_type, festival_type, summary, story, what_to_expect, how_to_participate, practical_info, cover_image_url, gallery_images, video_embeds, is_published, created_at, usual_month, date_2026_start, date_2026_end FROM festivals
-WHERE is_published = true
-ORDER BY name ASC
-`
-
-func (q *Queries) ListFestivals(ctx context.Context) ([]Festival, error) {
-	rows, err := q.db.Query(ctx, listFestivals)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []Festival{}
-	for rows.Next() {
-		var i Festival
-		if err := rows.Scan(
-			&i.ID,
-			&i.Slug,
-			&i.Name,
-			&i.DateType,
-			&i.Region,
-			&i.HeritageType,
-			&i.FestivalType,
-			&i.Summary,
-			&i.Story,
-			&i.WhatToExpect,
-			&i.HowToParticipate,
-			&i.PracticalInfo,
-			&i.CoverImageUrl,
-			&i.GalleryImages,
-			&i.VideoEmbeds,
-			&i.IsPublished,
-			&i.CreatedAt,
-			&i.UsualMonth,
-			&i.Date2026Start,
-			&i.Date2026End,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const listFestivalsByHeritage = `-- name: ListFestivalsByHeritage :many
-SELECT id, slug, name, date_type, region, heritage_type, festival_type, summary, story, what_to_expect, how_to_participate, practical_info, cover_image_url, gallery_images, video_embeds, is_published, created_at, usual_month, date_2026_start, date_2026_end FROM festivals
-WHERE is_published = true AND heritage_type = $1
-ORDER BY name ASC
+const getFestivalForUpdate = `-- name: GetFestivalForUpdate :one
+SELECT id, slug, name, date_type, region, heritage_type, festival_type, summary, story, what_to_expect, how_to_participate, practical_info, cover_image_url, gallery_images, video_embeds, is_published, created_at, usual_month, date_2026_start, date_2026_end, recurrence, updated_at FROM festivals
+WHERE id = $1
+FOR UPDATE
 `
 
-func (q *Queries) ListFestivalsByHeritage(ctx context.Context, heritageType string) ([]Festival, error) {
-	rows, err := q.db.Query(ctx, listFestivalsByHeritage, heritageType)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []Festival{}
-	for rows.Next() {
-		var i Festival
-		if err := rows.Scan(
-			&i.ID,
-			&i.Slug,
-			&i.Name,
-			&i.DateType,
-			&i.Region,
-			&i.HeritageType,
-			&i.FestivalType,
-			&i.Summary,
-			&i.Story,
-			&i.WhatToExpect,
-			&i.HowToParticipate,
-			&i.PracticalInfo,
-			&i.CoverImageUrl,
-			&i.GalleryImages,
-			&i.VideoEmbeds,
-			&i.IsPublished,
-			&i.CreatedAt,
-			&i.UsualMonth,
-			&i.Date2026Start,
-			&i.Date2026End,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) GetFestivalForUpdate(ctx context.Context, id pgtype.UUID) (Festival, error) {
+	row := q.db.QueryRow(ctx, getFestivalForUpdate, id)
+	var i Festival
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.DateType,
+		&i.Region,
+		&i.HeritageType,
+		&i.FestivalType,
+		&i.Summary,
+		&i.Story,
+		&i.WhatToExpect,
+		&i.HowToParticipate,
+		&i.PracticalInfo,
+		&i.CoverImageUrl,
+		&i.GalleryImages,
+		&i.VideoEmbeds,
+		&i.IsPublished,
+		&i.CreatedAt,
+		&i.UsualMonth,
+		&i.Date2026Start,
+		&i.Date2026End,
+		&i.Recurrence,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
-const listFestivalsByRegion = `-- name: ListFestivalsByRegion :many
-SELECT id, slug, name, date_type, region, heritage_type, festival_type, summary, story, what_to_expect, how_to_participate, practical_info, cover_image_url, gallery_images, video_embeds, is_published, created_at, usual_month, date_2026_start, date_2026_end FROM festivals
-WHERE is_published = true AND region = $1
+const listFestivalsWithRecurrence = `-- name: ListFestivalsWithRecurrence :many
+SELECT id, slug, name, date_type, region, heritage_type, festival_type, summary, story, what_to_expect, how_to_participate, practical_info, cover_image_url, gallery_images, video_embeds, is_published, created_at, usual_month, date_2026_start, date_2026_end, recurrence, updated_at FROM festivals
+WHERE recurrence IS NOT NULL
 ORDER BY name ASC
 `
 
-func (q *Queries) ListFestivalsByRegion(ctx context.Context, region string) ([]Festival, error) {
-	rows, err := q.db.Query(ctx, listFestivalsByRegion, region)
+func (q *Queries) ListFestivalsWithRecurrence(ctx context.Context) ([]Festival, error) {
+	rows, err := q.db.Query(ctx, listFestivalsWithRecurrence)
 	if err != nil {
 		return nil, err
 	}
@@ -289,6 +237,8 @@ func (q *Queries) ListFestivalsByRegion(ctx context.Context, region string) ([]F
 			&i.UsualMonth,
 			&i.Date2026Start,
 			&i.Date2026End,
+			&i.Recurrence,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -314,11 +264,10 @@ UPDATE festivals SET
     how_to_participate = $11,
     practical_info = $12,
     cover_image_url = $13,
-    gallery_images = $14,
-    video_embeds = $15,
-    is_published = $16
+    is_published = $14,
+    updated_at = clock_timestamp()
 WHERE id = $1
-RETURNING id, slug, name, date_type, region, heritage_type, festival_type, summary, story, what_to_expect, how_to_participate, practical_info, cover_image_url, gallery_images, video_embeds, is_published, created_at, usual_month, date_2026_start, date_2026_end
+RETURNING id, slug, name, date_type, region, heritage_type, festival_type, summary, story, what_to_expect, how_to_participate, practical_info, cover_image_url, gallery_images, video_embeds, is_published, created_at, usual_month, date_2026_start, date_2026_end, recurrence, updated_at
 `
 
 type UpdateFestivalParams struct {
@@ -335,8 +284,6 @@ type UpdateFestivalParams struct {
 	HowToParticipate pgtype.Text `json:"howToParticipate"`
 	PracticalInfo    pgtype.Text `json:"practicalInfo"`
 	CoverImageUrl    pgtype.Text `json:"coverImageUrl"`
-	GalleryImages    []byte      `json:"galleryImages"`
-	VideoEmbeds      []byte      `json:"videoEmbeds"`
 	IsPublished      pgtype.Bool `json:"isPublished"`
 }
 
@@ -355,8 +302,6 @@ func (q *Queries) UpdateFestival(ctx context.Context, arg UpdateFestivalParams)
 		arg.HowToParticipate,
 		arg.PracticalInfo,
 		arg.CoverImageUrl,
-		arg.GalleryImages,
-		arg.VideoEmbeds,
 		arg.IsPublished,
 	)
 	var i Festival
@@ -381,6 +326,96 @@ func (q *Queries) UpdateFestival(ctx context.Context, arg UpdateFestivalParams)
 		&i.UsualMonth,
 		&i.Date2026Start,
 		&i.Date2026End,
+		&i.Recurrence,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateFestivalMedia = `-- name: UpdateFestivalMedia :one
+UPDATE festivals SET
+    gallery_images = $2,
+    video_embeds = $3,
+    updated_at = clock_timestamp()
+WHERE id = $1
+RETURNING id, slug, name, date_type, region, heritage_type, festival_type, summary, story, what_to_expect, how_to_participate, practical_info, cover_image_url, gallery_images, video_embeds, is_published, created_at, usual_month, date_2026_start, date_2026_end, recurrence, updated_at
+`
+
+type UpdateFestivalMediaParams struct {
+	ID            pgtype.UUID `json:"id"`
+	GalleryImages []byte      `json:"galleryImages"`
+	VideoEmbeds   []byte      `json:"videoEmbeds"`
+}
+
+func (q *Queries) UpdateFestivalMedia(ctx context.Context, arg UpdateFestivalMediaParams) (Festival, error) {
+	row := q.db.QueryRow(ctx, updateFestivalMedia, arg.ID, arg.GalleryImages, arg.VideoEmbeds)
+	var i Festival
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.DateType,
+		&i.Region,
+		&i.HeritageType,
+		&i.FestivalType,
+		&i.Summary,
+		&i.Story,
+		&i.WhatToExpect,
+		&i.HowToParticipate,
+		&i.PracticalInfo,
+		&i.CoverImageUrl,
+		&i.GalleryImages,
+		&i.VideoEmbeds,
+		&i.IsPublished,
+		&i.CreatedAt,
+		&i.UsualMonth,
+		&i.Date2026Start,
+		&i.Date2026End,
+		&i.Recurrence,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateFestivalRecurrence = `-- name: UpdateFestivalRecurrence :one
+UPDATE festivals SET
+    recurrence = $2,
+    updated_at = clock_timestamp()
+WHERE id = $1
+RETURNING id, slug, name, date_type, region, heritage_type, festival_type, summary, story, what_to_expect, how_to_participate, practical_info, cover_image_url, gallery_images, video_embeds, is_published, created_at, usual_month, date_2026_start, date_2026_end, recurrence, updated_at
+`
+
+type UpdateFestivalRecurrenceParams struct {
+	ID         pgtype.UUID `json:"id"`
+	Recurrence []byte      `json:"recurrence"`
+}
+
+func (q *Queries) UpdateFestivalRecurrence(ctx context.Context, arg UpdateFestivalRecurrenceParams) (Festival, error) {
+	row := q.db.QueryRow(ctx, updateFestivalRecurrence, arg.ID, arg.Recurrence)
+	var i Festival
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.DateType,
+		&i.Region,
+		&i.HeritageType,
+		&i.FestivalType,
+		&i.Summary,
+		&i.Story,
+		&i.WhatToExpect,
+		&i.HowToParticipate,
+		&i.PracticalInfo,
+		&i.CoverImageUrl,
+		&i.GalleryImages,
+		&i.VideoEmbeds,
+		&i.IsPublished,
+		&i.CreatedAt,
+		&i.UsualMonth,
+		&i.Date2026Start,
+		&i.Date2026End,
+		&i.Recurrence,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
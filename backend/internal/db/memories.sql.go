@@ -11,20 +11,70 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const contentHashExists = `-- name: ContentHashExists :one
+SELECT (COUNT(*) > 0)::boolean AS found
+FROM memories
+WHERE content_hash = $1::text
+  AND submitted_at > NOW() - INTERVAL '90 days'
+`
+
+func (q *Queries) ContentHashExists(ctx context.Context, contentHash string) (bool, error) {
+	row := q.db.QueryRow(ctx, contentHashExists, contentHash)
+	var found bool
+	err := row.Scan(&found)
+	return found, err
+}
+
+const countMemories = `-- name: CountMemories :one
+SELECT COUNT(*) FROM memories
+WHERE ($1::text IS NULL OR status = $1)
+  AND ($2::uuid IS NULL OR festival_id = $2)
+  AND ($3::date IS NULL OR submitted_at >= $3)
+  AND ($4::date IS NULL OR submitted_at < $4 + 1)
+`
+
+type CountMemoriesParams struct {
+	Status        pgtype.Text `json:"status"`
+	FestivalID    pgtype.UUID `json:"festivalId"`
+	SubmittedFrom pgtype.Date `json:"submittedFrom"`
+	SubmittedTo   pgtype.Date `json:"submittedTo"`
+}
+
+func (q *Queries) CountMemories(ctx context.Context, arg CountMemoriesParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countMemories,
+		arg.Status,
+		arg.FestivalID,
+		arg.SubmittedFrom,
+		arg.SubmittedTo,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createMemory = `-- name: CreateMemory :one
 INSERT INTO memories (
-    festival_id, author_name, author_email, content, year_of_memory
+    festival_id, author_name, author_email, content, year_of_memory,
+    notify_author, author_token, status, content_hash, screening_score,
+    screening_results, flagged
 ) VALUES (
-    $1, $2, $3, $4, $5
-) RETURNING id, festival_id, author_name, author_email, content, year_of_memory, status, submitted_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+) RETURNING id, festival_id, author_name, author_email, content, year_of_memory, status, submitted_at, notify_author, author_token, content_hash, screening_score, screening_results, flagged
 `
 
 type CreateMemoryParams struct {
-	FestivalID   pgtype.UUID `json:"festivalId"`
-	AuthorName   pgtype.Text `json:"authorName"`
-	AuthorEmail  pgtype.Text `json:"authorEmail"`
-	Content      string      `json:"content"`
-	YearOfMemory pgtype.Text `json:"yearOfMemory"`
+	FestivalID       pgtype.UUID `json:"festivalId"`
+	AuthorName       pgtype.Text `json:"authorName"`
+	AuthorEmail      pgtype.Text `json:"authorEmail"`
+	Content          string      `json:"content"`
+	YearOfMemory     pgtype.Text `json:"yearOfMemory"`
+	NotifyAuthor     bool        `json:"notifyAuthor"`
+	AuthorToken      string      `json:"authorToken"`
+	Status           pgtype.Text `json:"status"`
+	ContentHash      pgtype.Text `json:"contentHash"`
+	ScreeningScore   int32       `json:"screeningScore"`
+	ScreeningResults []byte      `json:"screeningResults"`
+	Flagged          bool        `json:"flagged"`
 }
 
 func (q *Queries) CreateMemory(ctx context.Context, arg CreateMemoryParams) (Memory, error) {
@@ -34,6 +84,13 @@ func (q *Queries) CreateMemory(ctx context.Context, arg CreateMemoryParams) (Mem
 		arg.AuthorEmail,
 		arg.Content,
 		arg.YearOfMemory,
+		arg.NotifyAuthor,
+		arg.AuthorToken,
+		arg.Status,
+		arg.ContentHash,
+		arg.ScreeningScore,
+		arg.ScreeningResults,
+		arg.Flagged,
 	)
 	var i Memory
 	err := row.Scan(
@@ -45,6 +102,12 @@ func (q *Queries) CreateMemory(ctx context.Context, arg CreateMemoryParams) (Mem
 		&i.YearOfMemory,
 		&i.Status,
 		&i.SubmittedAt,
+		&i.NotifyAuthor,
+		&i.AuthorToken,
+		&i.ContentHash,
+		&i.ScreeningScore,
+		&i.ScreeningResults,
+		&i.Flagged,
 	)
 	return i, err
 }
@@ -59,8 +122,35 @@ func (q *Queries) DeleteMemory(ctx context.Context, id pgtype.UUID) error {
 	return err
 }
 
+const getMemoryByAuthorToken = `-- name: GetMemoryByAuthorToken :one
+SELECT id, festival_id, author_name, author_email, content, year_of_memory, status, submitted_at, notify_author, author_token, content_hash, screening_score, screening_results, flagged FROM memories
+WHERE author_token = $1
+`
+
+func (q *Queries) GetMemoryByAuthorToken(ctx context.Context, authorToken string) (Memory, error) {
+	row := q.db.QueryRow(ctx, getMemoryByAuthorToken, authorToken)
+	var i Memory
+	err := row.Scan(
+		&i.ID,
+		&i.FestivalID,
+		&i.AuthorName,
+		&i.AuthorEmail,
+		&i.Content,
+		&i.YearOfMemory,
+		&i.Status,
+		&i.SubmittedAt,
+		&i.NotifyAuthor,
+		&i.AuthorToken,
+		&i.ContentHash,
+		&i.ScreeningScore,
+		&i.ScreeningResults,
+		&i.Flagged,
+	)
+	return i, err
+}
+
 const getMemoryByID = `-- name: GetMemoryByID :one
-SELECT id, festival_id, author_name, author_email, content, year_of_memory, status, submitted_at FROM memories
+SELECT id, festival_id, author_name, author_email, content, year_of_memory, status, submitted_at, notify_author, author_token, content_hash, screening_score, screening_results, flagged FROM memories
 WHERE id = $1
 `
 
@@ -76,17 +166,52 @@ func (q *Queries) GetMemoryByID(ctx context.Context, id pgtype.UUID) (Memory, er
 		&i.YearOfMemory,
 		&i.Status,
 		&i.SubmittedAt,
+		&i.NotifyAuthor,
+		&i.AuthorToken,
+		&i.ContentHash,
+		&i.ScreeningScore,
+		&i.ScreeningResults,
+		&i.Flagged,
+	)
+	return i, err
+}
+
+const getMemoryForUpdate = `-- name: GetMemoryForUpdate :one
+SELECT id, festival_id, author_name, author_email, content, year_of_memory, status, submitted_at, notify_author, author_token, content_hash, screening_score, screening_results, flagged FROM memories
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetMemoryForUpdate(ctx context.Context, id pgtype.UUID) (Memory, error) {
+	row := q.db.QueryRow(ctx, getMemoryForUpdate, id)
+	var i Memory
+	err := row.Scan(
+		&i.ID,
+		&i.FestivalID,
+		&i.AuthorName,
+		&i.AuthorEmail,
+		&i.Content,
+		&i.YearOfMemory,
+		&i.Status,
+		&i.SubmittedAt,
+		&i.NotifyAuthor,
+		&i.AuthorToken,
+		&i.ContentHash,
+		&i.ScreeningScore,
+		&i.ScreeningResults,
+		&i.Flagged,
 	)
 	return i, err
 }
 
-const listAllMemories = `-- name: ListAllMemories :many
-SELECT id, festival_id, author_name, author_email, content, year_of_memory, status, submitted_at FROM memories
+const listMemoriesByFestival = `-- name: ListMemoriesByFestival :many
+SELECT id, festival_id, author_name, author_email, content, year_of_memory, status, submitted_at, notify_author, author_token, content_hash, screening_score, screening_results, flagged FROM memories
+WHERE festival_id = $1 AND status = 'approved'
 ORDER BY submitted_at DESC
 `
 
-func (q *Queries) ListAllMemories(ctx context.Context) ([]Memory, error) {
-	rows, err := q.db.Query(ctx, listAllMemories)
+func (q *Queries) ListMemoriesByFestival(ctx context.Context, festivalID pgtype.UUID) ([]Memory, error) {
+	rows, err := q.db.Query(ctx, listMemoriesByFestival, festivalID)
 	if err != nil {
 		return nil, err
 	}
@@ -103,6 +228,12 @@ func (q *Queries) ListAllMemories(ctx context.Context) ([]Memory, error) {
 			&i.YearOfMemory,
 			&i.Status,
 			&i.SubmittedAt,
+			&i.NotifyAuthor,
+			&i.AuthorToken,
+			&i.ContentHash,
+			&i.ScreeningScore,
+			&i.ScreeningResults,
+			&i.Flagged,
 		); err != nil {
 			return nil, err
 		}
@@ -114,14 +245,37 @@ func (q *Queries) ListAllMemories(ctx context.Context) ([]Memory, error) {
 	return items, nil
 }
 
-const listMemoriesByFestival = `-- name: ListMemoriesByFestival :many
-SELECT id, festival_id, author_name, author_email, content, year_of_memory, status, submitted_at FROM memories
-WHERE festival_id = $1 AND status = 'approved'
-ORDER BY submitted_at DESC
+const listMemoriesPage = `-- name: ListMemoriesPage :many
+SELECT id, festival_id, author_name, author_email, content, year_of_memory, status, submitted_at, notify_author, author_token, content_hash, screening_score, screening_results, flagged FROM memories
+WHERE ($1::text IS NULL OR status = $1)
+  AND ($2::uuid IS NULL OR festival_id = $2)
+  AND ($3::date IS NULL OR submitted_at >= $3)
+  AND ($4::date IS NULL OR submitted_at < $4 + 1)
+  AND ($5::uuid IS NULL OR (submitted_at, id) < ($6::timestamptz, $5))
+ORDER BY submitted_at DESC, id DESC
+LIMIT $7::int
 `
 
-func (q *Queries) ListMemoriesByFestival(ctx context.Context, festivalID pgtype.UUID) ([]Memory, error) {
-	rows, err := q.db.Query(ctx, listMemoriesByFestival, festivalID)
+type ListMemoriesPageParams struct {
+	Status           pgtype.Text        `json:"status"`
+	FestivalID       pgtype.UUID        `json:"festivalId"`
+	SubmittedFrom    pgtype.Date        `json:"submittedFrom"`
+	SubmittedTo      pgtype.Date        `json:"submittedTo"`
+	AfterID          pgtype.UUID        `json:"afterId"`
+	AfterSubmittedAt pgtype.Timestamptz `json:"afterSubmittedAt"`
+	MaxResults       int32              `json:"maxResults"`
+}
+
+func (q *Queries) ListMemoriesPage(ctx context.Context, arg ListMemoriesPageParams) ([]Memory, error) {
+	rows, err := q.db.Query(ctx, listMemoriesPage,
+		arg.Status,
+		arg.FestivalID,
+		arg.SubmittedFrom,
+		arg.SubmittedTo,
+		arg.AfterID,
+		arg.AfterSubmittedAt,
+		arg.MaxResults,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -138,6 +292,12 @@ func (q *Queries) ListMemoriesByFestival(ctx context.Context, festivalID pgtype.
 			&i.YearOfMemory,
 			&i.Status,
 			&i.SubmittedAt,
+			&i.NotifyAuthor,
+			&i.AuthorToken,
+			&i.ContentHash,
+			&i.ScreeningScore,
+			&i.ScreeningResults,
+			&i.Flagged,
 		); err != nil {
 			return nil, err
 		}
@@ -149,10 +309,66 @@ func (q *Queries) ListMemoriesByFestival(ctx context.Context, festivalID pgtype.
 	return items, nil
 }
 
-const updateMemoryStatus = `-- name: UpdateMemoryStatus :exec
+const listPendingMemories = `-- name: ListPendingMemories :many
+SELECT id, festival_id, author_name, author_email, content, year_of_memory, status, submitted_at, notify_author, author_token, content_hash, screening_score, screening_results, flagged FROM memories
+WHERE status = 'pending'
+  AND ($1::uuid IS NULL OR (NOT flagged, submitted_at, id) > (NOT $2::boolean, $3::timestamptz, $1))
+ORDER BY (NOT flagged), submitted_at ASC, id ASC
+LIMIT $4::int
+`
+
+type ListPendingMemoriesParams struct {
+	AfterID          pgtype.UUID        `json:"afterId"`
+	AfterFlagged     pgtype.Bool        `json:"afterFlagged"`
+	AfterSubmittedAt pgtype.Timestamptz `json:"afterSubmittedAt"`
+	MaxResults       int32              `json:"maxResults"`
+}
+
+func (q *Queries) ListPendingMemories(ctx context.Context, arg ListPendingMemoriesParams) ([]Memory, error) {
+	rows, err := q.db.Query(ctx, listPendingMemories,
+		arg.AfterID,
+		arg.AfterFlagged,
+		arg.AfterSubmittedAt,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Memory{}
+	for rows.Next() {
+		var i Memory
+		if err := rows.Scan(
+			&i.ID,
+			&i.FestivalID,
+			&i.AuthorName,
+			&i.AuthorEmail,
+			&i.Content,
+			&i.YearOfMemory,
+			&i.Status,
+			&i.SubmittedAt,
+			&i.NotifyAuthor,
+			&i.AuthorToken,
+			&i.ContentHash,
+			&i.ScreeningScore,
+			&i.ScreeningResults,
+			&i.Flagged,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateMemoryStatus = `-- name: UpdateMemoryStatus :one
 UPDATE memories
 SET status = $2
 WHERE id = $1
+RETURNING id, festival_id, author_name, author_email, content, year_of_memory, status, submitted_at, notify_author, author_token, content_hash, screening_score, screening_results, flagged
 `
 
 type UpdateMemoryStatusParams struct {
@@ -160,7 +376,24 @@ type UpdateMemoryStatusParams struct {
 	Status pgtype.Text `json:"status"`
 }
 
-func (q *Queries) UpdateMemoryStatus(ctx context.Context, arg UpdateMemoryStatusParams) error {
-	_, err := q.db.Exec(ctx, updateMemoryStatus, arg.ID, arg.Status)
-	return err
+func (q *Queries) UpdateMemoryStatus(ctx context.Context, arg UpdateMemoryStatusParams) (Memory, error) {
+	row := q.db.QueryRow(ctx, updateMemoryStatus, arg.ID, arg.Status)
+	var i Memory
+	err := row.Scan(
+		&i.ID,
+		&i.FestivalID,
+		&i.AuthorName,
+		&i.AuthorEmail,
+		&i.Content,
+		&i.YearOfMemory,
+		&i.Status,
+		&i.SubmittedAt,
+		&i.NotifyAuthor,
+		&i.AuthorToken,
+		&i.ContentHash,
+		&i.ScreeningScore,
+		&i.ScreeningResults,
+		&i.Flagged,
+	)
+	return i, err
 }
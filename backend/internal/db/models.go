@@ -8,6 +8,29 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type DigestDelivery struct {
+	ID             pgtype.UUID        `json:"id"`
+	SubscriptionID pgtype.UUID        `json:"subscriptionId"`
+	PeriodKey      string             `json:"periodKey"`
+	SentAt         pgtype.Timestamptz `json:"sentAt"`
+}
+
+type EmailOutbox struct {
+	ID                pgtype.UUID        `json:"id"`
+	ToEmail           string             `json:"toEmail"`
+	Subject           string             `json:"subject"`
+	HtmlBody          string             `json:"htmlBody"`
+	Status            string             `json:"status"`
+	Attempts          int32              `json:"attempts"`
+	NextAttemptAt     pgtype.Timestamptz `json:"nextAttemptAt"`
+	LastError         pgtype.Text        `json:"lastError"`
+	ProviderMessageID pgtype.Text        `json:"providerMessageId"`
+	CreatedAt         pgtype.Timestamptz `json:"createdAt"`
+	SentAt            pgtype.Timestamptz `json:"sentAt"`
+	TextBody          pgtype.Text        `json:"textBody"`
+	Headers           []byte             `json:"headers"`
+}
+
 type Festival struct {
 	ID               pgtype.UUID        `json:"id"`
 	Slug             string             `json:"slug"`
@@ -29,6 +52,8 @@ type Festival struct {
 	UsualMonth       pgtype.Text        `json:"usualMonth"`
 	Date2026Start    pgtype.Date        `json:"date2026Start"`
 	Date2026End      pgtype.Date        `json:"date2026End"`
+	Recurrence       []byte             `json:"recurrence"`
+	UpdatedAt        pgtype.Timestamptz `json:"updatedAt"`
 }
 
 type FestivalDate struct {
@@ -39,26 +64,89 @@ type FestivalDate struct {
 	EndDate     pgtype.Date        `json:"endDate"`
 	IsTentative pgtype.Bool        `json:"isTentative"`
 	CreatedAt   pgtype.Timestamptz `json:"createdAt"`
+	Source      string             `json:"source"`
+}
+
+type JobRun struct {
+	ID         pgtype.UUID        `json:"id"`
+	JobName    string             `json:"jobName"`
+	PeriodKey  string             `json:"periodKey"`
+	StartedAt  pgtype.Timestamptz `json:"startedAt"`
+	FinishedAt pgtype.Timestamptz `json:"finishedAt"`
+	Error      pgtype.Text        `json:"error"`
+}
+
+type MediaAsset struct {
+	ID               pgtype.UUID        `json:"id"`
+	OriginalFilename string             `json:"originalFilename"`
+	ContentType      string             `json:"contentType"`
+	SizeBytes        int64              `json:"sizeBytes"`
+	Width            int32              `json:"width"`
+	Height           int32              `json:"height"`
+	CreatedAt        pgtype.Timestamptz `json:"createdAt"`
+}
+
+type MediaVariant struct {
+	ID          pgtype.UUID `json:"id"`
+	AssetID     pgtype.UUID `json:"assetId"`
+	Width       int32       `json:"width"`
+	Height      int32       `json:"height"`
+	ContentType string      `json:"contentType"`
+	SizeBytes   int64       `json:"sizeBytes"`
+	StorageKey  string      `json:"storageKey"`
 }
 
 type Memory struct {
-	ID           pgtype.UUID        `json:"id"`
-	FestivalID   pgtype.UUID        `json:"festivalId"`
-	AuthorName   pgtype.Text        `json:"authorName"`
-	AuthorEmail  pgtype.Text        `json:"authorEmail"`
-	Content      string             `json:"content"`
-	YearOfMemory pgtype.Text        `json:"yearOfMemory"`
-	Status       pgtype.Text        `json:"status"`
-	SubmittedAt  pgtype.Timestamptz `json:"submittedAt"`
+	ID               pgtype.UUID        `json:"id"`
+	FestivalID       pgtype.UUID        `json:"festivalId"`
+	AuthorName       pgtype.Text        `json:"authorName"`
+	AuthorEmail      pgtype.Text        `json:"authorEmail"`
+	Content          string             `json:"content"`
+	YearOfMemory     pgtype.Text        `json:"yearOfMemory"`
+	Status           pgtype.Text        `json:"status"`
+	SubmittedAt      pgtype.Timestamptz `json:"submittedAt"`
+	NotifyAuthor     bool               `json:"notifyAuthor"`
+	AuthorToken      string             `json:"authorToken"`
+	ContentHash      pgtype.Text        `json:"contentHash"`
+	ScreeningScore   int32              `json:"screeningScore"`
+	ScreeningResults []byte             `json:"screeningResults"`
+	Flagged          bool               `json:"flagged"`
+}
+
+type MemoryAuthorOptout struct {
+	Email     string             `json:"email"`
+	CreatedAt pgtype.Timestamptz `json:"createdAt"`
+}
+
+type MemoryReview struct {
+	ID            pgtype.UUID        `json:"id"`
+	MemoryID      pgtype.UUID        `json:"memoryId"`
+	FromStatus    pgtype.Text        `json:"fromStatus"`
+	ToStatus      string             `json:"toStatus"`
+	Reviewer      string             `json:"reviewer"`
+	Reason        pgtype.Text        `json:"reason"`
+	Notes         pgtype.Text        `json:"notes"`
+	CreatedAt     pgtype.Timestamptz `json:"createdAt"`
+	AuthorMessage pgtype.Text        `json:"authorMessage"`
+}
+
+type ReminderDelivery struct {
+	ID             pgtype.UUID        `json:"id"`
+	SubscriptionID pgtype.UUID        `json:"subscriptionId"`
+	FestivalDateID pgtype.UUID        `json:"festivalDateId"`
+	DaysBefore     int32              `json:"daysBefore"`
+	SentAt         pgtype.Timestamptz `json:"sentAt"`
 }
 
 type Subscription struct {
-	ID                pgtype.UUID        `json:"id"`
-	Email             string             `json:"email"`
-	DigestWeekly      pgtype.Bool        `json:"digestWeekly"`
-	FestivalReminders []byte             `json:"festivalReminders"`
-	Confirmed         pgtype.Bool        `json:"confirmed"`
-	ConfirmationToken pgtype.Text        `json:"confirmationToken"`
-	UnsubscribeToken  string             `json:"unsubscribeToken"`
-	CreatedAt         pgtype.Timestamptz `json:"createdAt"`
+	ID                    pgtype.UUID        `json:"id"`
+	Email                 string             `json:"email"`
+	DigestWeekly          pgtype.Bool        `json:"digestWeekly"`
+	FestivalReminders     []byte             `json:"festivalReminders"`
+	Confirmed             pgtype.Bool        `json:"confirmed"`
+	ConfirmationToken     pgtype.Text        `json:"confirmationToken"`
+	UnsubscribeToken      string             `json:"unsubscribeToken"`
+	CreatedAt             pgtype.Timestamptz `json:"createdAt"`
+	ConfirmationExpiresAt pgtype.Timestamptz `json:"confirmationExpiresAt"`
+	CalendarToken         string             `json:"calendarToken"`
 }
@@ -13,7 +13,7 @@ import (
 
 const confirmSubscription = `-- name: ConfirmSubscription :exec
 UPDATE subscriptions
-SET confirmed = true, confirmation_token = NULL
+SET confirmed = true, confirmation_token = NULL, confirmation_expires_at = NULL
 WHERE id = $1
 `
 
@@ -22,20 +22,49 @@ func (q *Queries) ConfirmSubscription(ctx context.Context, id pgtype.UUID) error
 	return err
 }
 
+const countSubscriptions = `-- name: CountSubscriptions :one
+SELECT COUNT(*) FROM subscriptions
+WHERE ($1::boolean IS NULL OR confirmed = $1)
+  AND ($2::boolean IS NULL OR digest_weekly = $2)
+  AND ($3::date IS NULL OR created_at >= $3)
+  AND ($4::date IS NULL OR created_at < $4 + 1)
+`
+
+type CountSubscriptionsParams struct {
+	Confirmed    pgtype.Bool `json:"confirmed"`
+	DigestWeekly pgtype.Bool `json:"digestWeekly"`
+	CreatedFrom  pgtype.Date `json:"createdFrom"`
+	CreatedTo    pgtype.Date `json:"createdTo"`
+}
+
+func (q *Queries) CountSubscriptions(ctx context.Context, arg CountSubscriptionsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countSubscriptions,
+		arg.Confirmed,
+		arg.DigestWeekly,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createSubscription = `-- name: CreateSubscription :one
 INSERT INTO subscriptions (
-    email, digest_weekly, festival_reminders, confirmation_token, unsubscribe_token
+    email, digest_weekly, festival_reminders, confirmation_token, unsubscribe_token, confirmation_expires_at, calendar_token
 ) VALUES (
-    $1, $2, $3, $4, $5
-) RETURNING id, email, digest_weekly, festival_reminders, confirmed, confirmation_token, unsubscribe_token, created_at
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING id, email, digest_weekly, festival_reminders, confirmed, confirmation_token, unsubscribe_token, created_at, confirmation_expires_at, calendar_token
 `
 
 type CreateSubscriptionParams struct {
-	Email             string      `json:"email"`
-	DigestWeekly      pgtype.Bool `json:"digestWeekly"`
-	FestivalReminders []byte      `json:"festivalReminders"`
-	ConfirmationToken pgtype.Text `json:"confirmationToken"`
-	UnsubscribeToken  string      `json:"unsubscribeToken"`
+	Email                 string             `json:"email"`
+	DigestWeekly          pgtype.Bool        `json:"digestWeekly"`
+	FestivalReminders     []byte             `json:"festivalReminders"`
+	ConfirmationToken     pgtype.Text        `json:"confirmationToken"`
+	UnsubscribeToken      string             `json:"unsubscribeToken"`
+	ConfirmationExpiresAt pgtype.Timestamptz `json:"confirmationExpiresAt"`
+	CalendarToken         string             `json:"calendarToken"`
 }
 
 func (q *Queries) CreateSubscription(ctx context.Context, arg CreateSubscriptionParams) (Subscription, error) {
@@ -45,6 +74,8 @@ func (q *Queries) CreateSubscription(ctx context.Context, arg CreateSubscription
 		arg.FestivalReminders,
 		arg.ConfirmationToken,
 		arg.UnsubscribeToken,
+		arg.ConfirmationExpiresAt,
+		arg.CalendarToken,
 	)
 	var i Subscription
 	err := row.Scan(
@@ -56,10 +87,25 @@ func (q *Queries) CreateSubscription(ctx context.Context, arg CreateSubscription
 		&i.ConfirmationToken,
 		&i.UnsubscribeToken,
 		&i.CreatedAt,
+		&i.ConfirmationExpiresAt,
+		&i.CalendarToken,
 	)
 	return i, err
 }
 
+const deleteExpiredUnconfirmedSubscriptions = `-- name: DeleteExpiredUnconfirmedSubscriptions :execrows
+DELETE FROM subscriptions
+WHERE confirmed = false AND confirmation_expires_at < NOW()
+`
+
+func (q *Queries) DeleteExpiredUnconfirmedSubscriptions(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredUnconfirmedSubscriptions)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const deleteSubscription = `-- name: DeleteSubscription :exec
 DELETE FROM subscriptions
 WHERE id = $1
@@ -70,8 +116,31 @@ func (q *Queries) DeleteSubscription(ctx context.Context, id pgtype.UUID) error
 	return err
 }
 
+const getSubscriptionByCalendarToken = `-- name: GetSubscriptionByCalendarToken :one
+SELECT id, email, digest_weekly, festival_reminders, confirmed, confirmation_token, unsubscribe_token, created_at, confirmation_expires_at, calendar_token FROM subscriptions
+WHERE calendar_token = $1 AND confirmed = true
+`
+
+func (q *Queries) GetSubscriptionByCalendarToken(ctx context.Context, calendarToken string) (Subscription, error) {
+	row := q.db.QueryRow(ctx, getSubscriptionByCalendarToken, calendarToken)
+	var i Subscription
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.DigestWeekly,
+		&i.FestivalReminders,
+		&i.Confirmed,
+		&i.ConfirmationToken,
+		&i.UnsubscribeToken,
+		&i.CreatedAt,
+		&i.ConfirmationExpiresAt,
+		&i.CalendarToken,
+	)
+	return i, err
+}
+
 const getSubscriptionByConfirmationToken = `-- name: GetSubscriptionByConfirmationToken :one
-SELECT id, email, digest_weekly, festival_reminders, confirmed, confirmation_token, unsubscribe_token, created_at FROM subscriptions
+SELECT id, email, digest_weekly, festival_reminders, confirmed, confirmation_token, unsubscribe_token, created_at, confirmation_expires_at, calendar_token FROM subscriptions
 WHERE confirmation_token = $1
 `
 
@@ -87,12 +156,14 @@ func (q *Queries) GetSubscriptionByConfirmationToken(ctx context.Context, confir
 		&i.ConfirmationToken,
 		&i.UnsubscribeToken,
 		&i.CreatedAt,
+		&i.ConfirmationExpiresAt,
+		&i.CalendarToken,
 	)
 	return i, err
 }
 
 const getSubscriptionByEmail = `-- name: GetSubscriptionByEmail :one
-SELECT id, email, digest_weekly, festival_reminders, confirmed, confirmation_token, unsubscribe_token, created_at FROM subscriptions
+SELECT id, email, digest_weekly, festival_reminders, confirmed, confirmation_token, unsubscribe_token, created_at, confirmation_expires_at, calendar_token FROM subscriptions
 WHERE email = $1
 `
 
@@ -108,12 +179,37 @@ func (q *Queries) GetSubscriptionByEmail(ctx context.Context, email string) (Sub
 		&i.ConfirmationToken,
 		&i.UnsubscribeToken,
 		&i.CreatedAt,
+		&i.ConfirmationExpiresAt,
+		&i.CalendarToken,
+	)
+	return i, err
+}
+
+const getSubscriptionByID = `-- name: GetSubscriptionByID :one
+SELECT id, email, digest_weekly, festival_reminders, confirmed, confirmation_token, unsubscribe_token, created_at, confirmation_expires_at, calendar_token FROM subscriptions
+WHERE id = $1
+`
+
+func (q *Queries) GetSubscriptionByID(ctx context.Context, id pgtype.UUID) (Subscription, error) {
+	row := q.db.QueryRow(ctx, getSubscriptionByID, id)
+	var i Subscription
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.DigestWeekly,
+		&i.FestivalReminders,
+		&i.Confirmed,
+		&i.ConfirmationToken,
+		&i.UnsubscribeToken,
+		&i.CreatedAt,
+		&i.ConfirmationExpiresAt,
+		&i.CalendarToken,
 	)
 	return i, err
 }
 
 const getSubscriptionByUnsubscribeToken = `-- name: GetSubscriptionByUnsubscribeToken :one
-SELECT id, email, digest_weekly, festival_reminders, confirmed, confirmation_token, unsubscribe_token, created_at FROM subscriptions
+SELECT id, email, digest_weekly, festival_reminders, confirmed, confirmation_token, unsubscribe_token, created_at, confirmation_expires_at, calendar_token FROM subscriptions
 WHERE unsubscribe_token = $1
 `
 
@@ -129,17 +225,19 @@ func (q *Queries) GetSubscriptionByUnsubscribeToken(ctx context.Context, unsubsc
 		&i.ConfirmationToken,
 		&i.UnsubscribeToken,
 		&i.CreatedAt,
+		&i.ConfirmationExpiresAt,
+		&i.CalendarToken,
 	)
 	return i, err
 }
 
-const listAllSubscriptions = `-- name: ListAllSubscriptions :many
-SELECT id, email, digest_weekly, festival_reminders, confirmed, confirmation_token, unsubscribe_token, created_at FROM subscriptions
-ORDER BY created_at DESC
+const listConfirmedWeeklyDigest = `-- name: ListConfirmedWeeklyDigest :many
+SELECT id, email, digest_weekly, festival_reminders, confirmed, confirmation_token, unsubscribe_token, created_at, confirmation_expires_at, calendar_token FROM subscriptions
+WHERE confirmed = true AND digest_weekly = true
 `
 
-func (q *Queries) ListAllSubscriptions(ctx context.Context) ([]Subscription, error) {
-	rows, err := q.db.Query(ctx, listAllSubscriptions)
+func (q *Queries) ListConfirmedWeeklyDigest(ctx context.Context) ([]Subscription, error) {
+	rows, err := q.db.Query(ctx, listConfirmedWeeklyDigest)
 	if err != nil {
 		return nil, err
 	}
@@ -156,6 +254,8 @@ func (q *Queries) ListAllSubscriptions(ctx context.Context) ([]Subscription, err
 			&i.ConfirmationToken,
 			&i.UnsubscribeToken,
 			&i.CreatedAt,
+			&i.ConfirmationExpiresAt,
+			&i.CalendarToken,
 		); err != nil {
 			return nil, err
 		}
@@ -167,13 +267,13 @@ func (q *Queries) ListAllSubscriptions(ctx context.Context) ([]Subscription, err
 	return items, nil
 }
 
-const listConfirmedWeeklyDigest = `-- name: ListConfirmedWeeklyDigest :many
-SELECT id, email, digest_weekly, festival_reminders, confirmed, confirmation_token, unsubscribe_token, created_at FROM subscriptions
-WHERE confirmed = true AND digest_weekly = true
+const listConfirmedWithReminders = `-- name: ListConfirmedWithReminders :many
+SELECT id, email, digest_weekly, festival_reminders, confirmed, confirmation_token, unsubscribe_token, created_at, confirmation_expires_at, calendar_token FROM subscriptions
+WHERE confirmed = true AND festival_reminders <> '[]'::jsonb
 `
 
-func (q *Queries) ListConfirmedWeeklyDigest(ctx context.Context) ([]Subscription, error) {
-	rows, err := q.db.Query(ctx, listConfirmedWeeklyDigest)
+func (q *Queries) ListConfirmedWithReminders(ctx context.Context) ([]Subscription, error) {
+	rows, err := q.db.Query(ctx, listConfirmedWithReminders)
 	if err != nil {
 		return nil, err
 	}
@@ -190,6 +290,8 @@ func (q *Queries) ListConfirmedWeeklyDigest(ctx context.Context) ([]Subscription
 			&i.ConfirmationToken,
 			&i.UnsubscribeToken,
 			&i.CreatedAt,
+			&i.ConfirmationExpiresAt,
+			&i.CalendarToken,
 		); err != nil {
 			return nil, err
 		}
@@ -200,3 +302,154 @@ func (q *Queries) ListConfirmedWeeklyDigest(ctx context.Context) ([]Subscription
 	}
 	return items, nil
 }
+
+const listSubscriptionsPage = `-- name: ListSubscriptionsPage :many
+SELECT id, email, digest_weekly, festival_reminders, confirmed, confirmation_token, unsubscribe_token, created_at, confirmation_expires_at, calendar_token FROM subscriptions
+WHERE ($1::boolean IS NULL OR confirmed = $1)
+  AND ($2::boolean IS NULL OR digest_weekly = $2)
+  AND ($3::date IS NULL OR created_at >= $3)
+  AND ($4::date IS NULL OR created_at < $4 + 1)
+  AND ($5::uuid IS NULL OR (created_at, id) < ($6::timestamptz, $5))
+ORDER BY created_at DESC, id DESC
+LIMIT $7::int
+`
+
+type ListSubscriptionsPageParams struct {
+	Confirmed      pgtype.Bool        `json:"confirmed"`
+	DigestWeekly   pgtype.Bool        `json:"digestWeekly"`
+	CreatedFrom    pgtype.Date        `json:"createdFrom"`
+	CreatedTo      pgtype.Date        `json:"createdTo"`
+	AfterID        pgtype.UUID        `json:"afterId"`
+	AfterCreatedAt pgtype.Timestamptz `json:"afterCreatedAt"`
+	MaxResults     int32              `json:"maxResults"`
+}
+
+func (q *Queries) ListSubscriptionsPage(ctx context.Context, arg ListSubscriptionsPageParams) ([]Subscription, error) {
+	rows, err := q.db.Query(ctx, listSubscriptionsPage,
+		arg.Confirmed,
+		arg.DigestWeekly,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.AfterID,
+		arg.AfterCreatedAt,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Subscription{}
+	for rows.Next() {
+		var i Subscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.DigestWeekly,
+			&i.FestivalReminders,
+			&i.Confirmed,
+			&i.ConfirmationToken,
+			&i.UnsubscribeToken,
+			&i.CreatedAt,
+			&i.ConfirmationExpiresAt,
+			&i.CalendarToken,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const refreshConfirmationToken = `-- name: RefreshConfirmationToken :one
+UPDATE subscriptions
+SET confirmation_token = $2, confirmation_expires_at = $3
+WHERE id = $1 AND confirmed = false
+RETURNING id, email, digest_weekly, festival_reminders, confirmed, confirmation_token, unsubscribe_token, created_at, confirmation_expires_at, calendar_token
+`
+
+type RefreshConfirmationTokenParams struct {
+	ID                    pgtype.UUID        `json:"id"`
+	ConfirmationToken     pgtype.Text        `json:"confirmationToken"`
+	ConfirmationExpiresAt pgtype.Timestamptz `json:"confirmationExpiresAt"`
+}
+
+func (q *Queries) RefreshConfirmationToken(ctx context.Context, arg RefreshConfirmationTokenParams) (Subscription, error) {
+	row := q.db.QueryRow(ctx, refreshConfirmationToken, arg.ID, arg.ConfirmationToken, arg.ConfirmationExpiresAt)
+	var i Subscription
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.DigestWeekly,
+		&i.FestivalReminders,
+		&i.Confirmed,
+		&i.ConfirmationToken,
+		&i.UnsubscribeToken,
+		&i.CreatedAt,
+		&i.ConfirmationExpiresAt,
+		&i.CalendarToken,
+	)
+	return i, err
+}
+
+const updateCalendarToken = `-- name: UpdateCalendarToken :one
+UPDATE subscriptions SET
+    calendar_token = $2
+WHERE id = $1
+RETURNING id, email, digest_weekly, festival_reminders, confirmed, confirmation_token, unsubscribe_token, created_at, confirmation_expires_at, calendar_token
+`
+
+type UpdateCalendarTokenParams struct {
+	ID            pgtype.UUID `json:"id"`
+	CalendarToken string      `json:"calendarToken"`
+}
+
+func (q *Queries) UpdateCalendarToken(ctx context.Context, arg UpdateCalendarTokenParams) (Subscription, error) {
+	row := q.db.QueryRow(ctx, updateCalendarToken, arg.ID, arg.CalendarToken)
+	var i Subscription
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.DigestWeekly,
+		&i.FestivalReminders,
+		&i.Confirmed,
+		&i.ConfirmationToken,
+		&i.UnsubscribeToken,
+		&i.CreatedAt,
+		&i.ConfirmationExpiresAt,
+		&i.CalendarToken,
+	)
+	return i, err
+}
+
+const updateSubscriptionReminders = `-- name: UpdateSubscriptionReminders :one
+UPDATE subscriptions
+SET festival_reminders = $2
+WHERE id = $1
+RETURNING id, email, digest_weekly, festival_reminders, confirmed, confirmation_token, unsubscribe_token, created_at, confirmation_expires_at, calendar_token
+`
+
+type UpdateSubscriptionRemindersParams struct {
+	ID                pgtype.UUID `json:"id"`
+	FestivalReminders []byte      `json:"festivalReminders"`
+}
+
+func (q *Queries) UpdateSubscriptionReminders(ctx context.Context, arg UpdateSubscriptionRemindersParams) (Subscription, error) {
+	row := q.db.QueryRow(ctx, updateSubscriptionReminders, arg.ID, arg.FestivalReminders)
+	var i Subscription
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.DigestWeekly,
+		&i.FestivalReminders,
+		&i.Confirmed,
+		&i.ConfirmationToken,
+		&i.UnsubscribeToken,
+		&i.CreatedAt,
+		&i.ConfirmationExpiresAt,
+		&i.CalendarToken,
+	)
+	return i, err
+}
@@ -0,0 +1,49 @@
+package email
+
+import (
+    "context"
+    "fmt"
+    "time"
+)
+
+// ConfirmationData is the data for the "confirmation" email.
+type ConfirmationData struct {
+    ConfirmURL string
+    ExpiresIn  string
+}
+
+func init() {
+    Register("confirmation", func(d ConfirmationData) (string, TemplateData) {
+        return "Confirm your subscription to KULTUR", TemplateData{
+            PreviewText: "Confirm your email to discover Trinidad's cultural festivals",
+            Heading:     "Confirm Your Email",
+            ButtonText:  "Confirm Email Address",
+            ButtonURL:   d.ConfirmURL,
+        }
+    })
+}
+
+func (s *Service) SendConfirmation(ctx context.Context, toEmail, token string, expiresIn time.Duration) error {
+    return s.Send(ctx, toEmail, "confirmation", s.ConfirmationData(token, expiresIn))
+}
+
+func (s *Service) ConfirmationData(token string, expiresIn time.Duration) ConfirmationData {
+    return ConfirmationData{
+        ConfirmURL: fmt.Sprintf("%s/api/subscribe/confirm/%s", s.baseURL, token),
+        ExpiresIn:  formatDuration(expiresIn),
+    }
+}
+
+// formatDuration renders a link lifetime for email copy, e.g. "24 hours".
+func formatDuration(d time.Duration) string {
+    switch {
+    case d >= 48*time.Hour && d%(24*time.Hour) == 0:
+        return fmt.Sprintf("%d days", int(d/(24*time.Hour)))
+    case d >= 2*time.Hour:
+        return fmt.Sprintf("%d hours", int(d/time.Hour))
+    case d >= time.Hour:
+        return "1 hour"
+    default:
+        return fmt.Sprintf("%d minutes", int(d/time.Minute))
+    }
+}
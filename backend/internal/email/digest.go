@@ -0,0 +1,59 @@
+package email
+
+import (
+    "context"
+    "fmt"
+)
+
+type FestivalDigestItem struct {
+    Name     string
+    Slug     string
+    Date     string
+    Heritage string
+    Region   string
+    // URL is filled in from Slug when the digest is sent.
+    URL string
+}
+
+// DigestData is the data for the "digest" email.
+type DigestData struct {
+    Festivals      []FestivalDigestItem
+    CalendarURL    string
+    UnsubscribeURL string
+}
+
+func init() {
+    Register("digest", func(d DigestData) (string, TemplateData) {
+        return fmt.Sprintf("This Week in T&T: %d Festivals Coming Up", len(d.Festivals)), TemplateData{
+            PreviewText:    fmt.Sprintf("%d festivals coming up this week in T&T", len(d.Festivals)),
+            Heading:        "This Week in T&T",
+            ButtonText:     "View Full Calendar",
+            ButtonURL:      d.CalendarURL,
+            FooterText:     "You're receiving this because you subscribed to KULTUR festival updates.",
+            UnsubscribeURL: d.UnsubscribeURL,
+        }
+    })
+}
+
+func (s *Service) SendWeeklyDigest(ctx context.Context, toEmail string, festivals []FestivalDigestItem, unsubscribeToken string) error {
+    if len(festivals) == 0 {
+        return nil // Don't send empty digest
+    }
+
+    return s.Send(ctx, toEmail, "digest", s.DigestData(festivals, unsubscribeToken))
+}
+
+// DigestData links each festival to its page on the site.
+func (s *Service) DigestData(festivals []FestivalDigestItem, unsubscribeToken string) DigestData {
+    items := make([]FestivalDigestItem, len(festivals))
+    for i, f := range festivals {
+        f.URL = fmt.Sprintf("%s/festivals/%s", s.baseURL, f.Slug)
+        items[i] = f
+    }
+
+    return DigestData{
+        Festivals:      items,
+        CalendarURL:    fmt.Sprintf("%s/festivals", s.baseURL),
+        UnsubscribeURL: s.unsubscribeURL(unsubscribeToken),
+    }
+}
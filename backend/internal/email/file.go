@@ -0,0 +1,44 @@
+package email
+
+import (
+    "context"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+    "time"
+)
+
+// FileSender writes every message to dir as an .eml file instead of sending
+// it, so emails can be opened in a mail client during development.
+type FileSender struct {
+    dir string
+}
+
+func NewFileSender(dir string) (*FileSender, error) {
+    if dir == "" {
+        dir = "tmp/emails"
+    }
+
+    if err := os.MkdirAll(dir, 0o755); err != nil {
+        return nil, fmt.Errorf("failed to create email directory: %w", err)
+    }
+
+    return &FileSender{dir: dir}, nil
+}
+
+func (f *FileSender) Send(ctx context.Context, msg Message) (string, error) {
+    raw, messageID, err := buildMIME(msg)
+    if err != nil {
+        return "", err
+    }
+
+    id := strings.Trim(messageID, "<>")
+    name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405"), strings.SplitN(id, "@", 2)[0])
+
+    if err := os.WriteFile(filepath.Join(f.dir, name), raw, 0o644); err != nil {
+        return "", err
+    }
+
+    return messageID, nil
+}
@@ -0,0 +1,55 @@
+package email
+
+import (
+    "fmt"
+    "slices"
+)
+
+// kind is a registered type of email. Its body is the embedded template
+// templates/<name>.html.
+type kind struct {
+    layout func(data any) (string, TemplateData, error)
+}
+
+var kinds = map[string]kind{}
+
+// Register adds an email kind whose body is templates/<name>.html, executed
+// with the data the email is sent with. layout returns the subject and the
+// base template fields around the body; Body and Year are filled in when the
+// email is rendered.
+//
+// Register is meant to be called from init and panics when name is
+// registered twice or has no body template.
+func Register[T any](name string, layout func(data T) (subject string, base TemplateData)) {
+    if _, ok := kinds[name]; ok {
+        panic(fmt.Sprintf("email: kind %q registered twice", name))
+    }
+
+    if bodies.Lookup(name+".html") == nil {
+        panic(fmt.Sprintf("email: no template for kind %q", name))
+    }
+
+    kinds[name] = kind{
+        layout: func(data any) (string, TemplateData, error) {
+            d, ok := data.(T)
+            if !ok {
+                var want T
+                return "", TemplateData{}, fmt.Errorf("email: kind %q expects %T, got %T", name, want, data)
+            }
+
+            subject, base := layout(d)
+            return subject, base, nil
+        },
+    }
+}
+
+// Kinds lists the registered email kinds by name.
+func Kinds() []string {
+    names := make([]string, 0, len(kinds))
+    for name := range kinds {
+        names = append(names, name)
+    }
+    slices.Sort(names)
+
+    return names
+}
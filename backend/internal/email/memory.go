@@ -0,0 +1,41 @@
+package email
+
+import (
+    "context"
+    "fmt"
+    "sync"
+)
+
+// MemorySender records messages instead of sending them, for tests.
+type MemorySender struct {
+    mu       sync.Mutex
+    messages []Message
+}
+
+func NewMemorySender() *MemorySender {
+    return &MemorySender{}
+}
+
+func (m *MemorySender) Send(ctx context.Context, msg Message) (string, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.messages = append(m.messages, msg)
+
+    return fmt.Sprintf("memory-%d", len(m.messages)), nil
+}
+
+// Messages returns a copy of every message sent so far.
+func (m *MemorySender) Messages() []Message {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    return append([]Message(nil), m.messages...)
+}
+
+func (m *MemorySender) Reset() {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.messages = nil
+}
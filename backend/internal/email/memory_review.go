@@ -0,0 +1,65 @@
+package email
+
+import (
+    "context"
+    "fmt"
+)
+
+// MemoryReviewData is the data for the "memory-approved" and
+// "memory-rejected" emails sent to a memory's author. Message is the
+// moderator's optional note, only shown on rejections.
+type MemoryReviewData struct {
+    AuthorName     string
+    FestivalName   string
+    FestivalURL    string
+    MemoryURL      string
+    Message        string
+    UnsubscribeURL string
+}
+
+const memoryReviewFooter = "You're receiving this because you shared a memory on KULTUR."
+
+func init() {
+    Register("memory-approved", func(d MemoryReviewData) (string, TemplateData) {
+        return fmt.Sprintf("Your %s memory is live! 🎉", d.FestivalName), TemplateData{
+            PreviewText:    fmt.Sprintf("Your memory of %s has been published", d.FestivalName),
+            Heading:        "Your Memory Is Live!",
+            ButtonText:     "See Your Memory",
+            ButtonURL:      d.MemoryURL,
+            FooterText:     memoryReviewFooter,
+            UnsubscribeURL: d.UnsubscribeURL,
+        }
+    })
+
+    Register("memory-rejected", func(d MemoryReviewData) (string, TemplateData) {
+        return fmt.Sprintf("About your %s memory", d.FestivalName), TemplateData{
+            PreviewText:    fmt.Sprintf("An update on your memory of %s", d.FestivalName),
+            Heading:        "About Your Memory",
+            ButtonText:     "Visit the Festival Page",
+            ButtonURL:      d.FestivalURL,
+            FooterText:     memoryReviewFooter,
+            UnsubscribeURL: d.UnsubscribeURL,
+        }
+    })
+}
+
+// SendMemoryReview tells a memory's author it was approved or rejected.
+// kind is "memory-approved" or "memory-rejected".
+func (s *Service) SendMemoryReview(ctx context.Context, toEmail, kind string, data MemoryReviewData) error {
+    return s.Send(ctx, toEmail, kind, data)
+}
+
+// MemoryReviewData links to the memory on its festival page. authorToken
+// identifies the memory in the opt-out link.
+func (s *Service) MemoryReviewData(authorName, festivalName, festivalSlug, memoryID, authorToken, message string) MemoryReviewData {
+    festivalURL := fmt.Sprintf("%s/festivals/%s", s.baseURL, festivalSlug)
+
+    return MemoryReviewData{
+        AuthorName:     authorName,
+        FestivalName:   festivalName,
+        FestivalURL:    festivalURL,
+        MemoryURL:      festivalURL + "#memory-" + memoryID,
+        Message:        message,
+        UnsubscribeURL: fmt.Sprintf("%s/api/memories/unsubscribe/%s", s.baseURL, authorToken),
+    }
+}
@@ -0,0 +1,115 @@
+package email
+
+import (
+    "context"
+    "strings"
+    "testing"
+)
+
+func TestMemorySender(t *testing.T) {
+    sender := NewMemorySender()
+    svc := NewService(Config{
+        Sender:  sender,
+        BaseURL: "https://kultur.example",
+    })
+
+    if err := svc.SendWelcome(context.Background(), "ana@example.com", "unsub-token"); err != nil {
+        t.Fatalf("SendWelcome: %v", err)
+    }
+
+    msgs := sender.Messages()
+    if len(msgs) != 1 {
+        t.Fatalf("got %d messages, want 1", len(msgs))
+    }
+
+    msg := msgs[0]
+    unsubscribeURL := "https://kultur.example/api/unsubscribe/unsub-token"
+
+    if msg.From != "KULTUR <noreply@kulturtt.com>" {
+        t.Errorf("From = %q", msg.From)
+    }
+    if msg.To != "ana@example.com" {
+        t.Errorf("To = %q", msg.To)
+    }
+    if msg.Subject != "Welcome to KULTUR! 🎭" {
+        t.Errorf("Subject = %q", msg.Subject)
+    }
+
+    headers := map[string]string{
+        "List-Unsubscribe":      "<" + unsubscribeURL + ">",
+        "List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+    }
+    for k, want := range headers {
+        if got := msg.Headers[k]; got != want {
+            t.Errorf("header %s = %q, want %q", k, got, want)
+        }
+    }
+
+    for _, want := range []string{"You&#39;re All Set!", `href="https://kultur.example/festivals"`, `href="` + unsubscribeURL + `"`} {
+        if !strings.Contains(msg.HTML, want) {
+            t.Errorf("HTML is missing %q", want)
+        }
+    }
+
+    for _, want := range []string{"You're All Set!", "Explore Festivals: https://kultur.example/festivals", "Unsubscribe: " + unsubscribeURL} {
+        if !strings.Contains(msg.Text, want) {
+            t.Errorf("Text is missing %q:\n%s", want, msg.Text)
+        }
+    }
+}
+
+func TestMemorySenderIDsAndReset(t *testing.T) {
+    sender := NewMemorySender()
+    svc := NewService(Config{Sender: sender})
+    ctx := context.Background()
+
+    for i, want := range []string{"memory-1", "memory-2"} {
+        id, err := svc.Deliver(ctx, Message{To: "ana@example.com", Subject: "hello"})
+        if err != nil {
+            t.Fatalf("Deliver %d: %v", i, err)
+        }
+        if id != want {
+            t.Errorf("Deliver %d = %q, want %q", i, id, want)
+        }
+    }
+
+    // the copy must not share the sender's backing array
+    msgs := sender.Messages()
+    msgs[0].Subject = "changed"
+    if got := sender.Messages()[0].Subject; got != "hello" {
+        t.Errorf("Messages returned a shared slice, subject is %q", got)
+    }
+
+    sender.Reset()
+    if got := len(sender.Messages()); got != 0 {
+        t.Errorf("got %d messages after Reset, want 0", got)
+    }
+}
+
+type recordingQueue struct {
+    messages []Message
+}
+
+func (q *recordingQueue) Enqueue(ctx context.Context, msg Message) error {
+    q.messages = append(q.messages, msg)
+    return nil
+}
+
+func TestQueuedMessagesSkipSender(t *testing.T) {
+    sender := NewMemorySender()
+    queue := &recordingQueue{}
+
+    svc := NewService(Config{Sender: sender})
+    svc.UseQueue(queue)
+
+    if err := svc.SendWelcome(context.Background(), "ana@example.com", "unsub-token"); err != nil {
+        t.Fatalf("SendWelcome: %v", err)
+    }
+
+    if got := len(sender.Messages()); got != 0 {
+        t.Errorf("sender got %d messages, want 0", got)
+    }
+    if got := len(queue.messages); got != 1 {
+        t.Errorf("queue got %d messages, want 1", got)
+    }
+}
@@ -0,0 +1,148 @@
+package email
+
+import (
+    "bytes"
+    "crypto/rand"
+    "encoding/hex"
+    "fmt"
+    "io"
+    "mime"
+    "mime/multipart"
+    "mime/quotedprintable"
+    "net/mail"
+    "net/textproto"
+    "sort"
+    "strings"
+    "time"
+)
+
+// buildMIME renders msg as an RFC 5322 message for the SMTP and file
+// senders, returning the raw bytes and the generated Message-ID.
+func buildMIME(msg Message) ([]byte, string, error) {
+    from, err := mail.ParseAddress(msg.From)
+    if err != nil {
+        return nil, "", fmt.Errorf("invalid from address: %w", err)
+    }
+
+    to, err := mail.ParseAddress(msg.To)
+    if err != nil {
+        return nil, "", fmt.Errorf("invalid to address: %w", err)
+    }
+
+    messageID, err := newMessageID(from.Address)
+    if err != nil {
+        return nil, "", err
+    }
+
+    headers := [][2]string{
+        {"From", from.String()},
+        {"To", to.String()},
+        {"Subject", mime.QEncoding.Encode("utf-8", msg.Subject)},
+        {"Date", time.Now().Format(time.RFC1123Z)},
+        {"Message-ID", messageID},
+        {"MIME-Version", "1.0"},
+    }
+
+    keys := make([]string, 0, len(msg.Headers))
+    for k := range msg.Headers {
+        keys = append(keys, k)
+    }
+    sort.Strings(keys)
+
+    for _, k := range keys {
+        headers = append(headers, [2]string{k, msg.Headers[k]})
+    }
+
+    var buf bytes.Buffer
+    for _, h := range headers {
+        if err := writeHeader(&buf, h[0], h[1]); err != nil {
+            return nil, "", err
+        }
+    }
+
+    if msg.Text == "" {
+        buf.WriteString("Content-Type: text/html; charset=\"utf-8\"\r\n")
+        buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+        buf.WriteString("\r\n")
+
+        if err := writeQuotedPrintable(&buf, msg.HTML); err != nil {
+            return nil, "", err
+        }
+
+        return buf.Bytes(), messageID, nil
+    }
+
+    mw := multipart.NewWriter(&buf)
+    fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=\"%s\"\r\n", mw.Boundary())
+    buf.WriteString("\r\n")
+
+    // least preferred part first, per RFC 2046
+    parts := []struct {
+        contentType string
+        body        string
+    }{
+        {`text/plain; charset="utf-8"`, msg.Text},
+        {`text/html; charset="utf-8"`, msg.HTML},
+    }
+
+    for _, part := range parts {
+        pw, err := mw.CreatePart(textproto.MIMEHeader{
+            "Content-Type":              {part.contentType},
+            "Content-Transfer-Encoding": {"quoted-printable"},
+        })
+        if err != nil {
+            return nil, "", err
+        }
+
+        if err := writeQuotedPrintable(pw, part.body); err != nil {
+            return nil, "", err
+        }
+    }
+
+    if err := mw.Close(); err != nil {
+        return nil, "", err
+    }
+
+    return buf.Bytes(), messageID, nil
+}
+
+func writeQuotedPrintable(w io.Writer, body string) error {
+    qp := quotedprintable.NewWriter(w)
+    if _, err := qp.Write([]byte(body)); err != nil {
+        return err
+    }
+
+    return qp.Close()
+}
+
+// writeHeader refuses line breaks, which would end the header early and let a
+// value such as an address inject headers of its own.
+func writeHeader(buf *bytes.Buffer, key, value string) error {
+    if key == "" || strings.ContainsAny(key, "\r\n: ") {
+        return fmt.Errorf("invalid header name %q", key)
+    }
+    if strings.ContainsAny(value, "\r\n") {
+        return fmt.Errorf("header %s contains a line break", key)
+    }
+
+    buf.WriteString(key)
+    buf.WriteString(": ")
+    buf.WriteString(value)
+    buf.WriteString("\r\n")
+
+    return nil
+}
+
+func newMessageID(fromAddress string) (string, error) {
+    b := make([]byte, 16)
+    if _, err := rand.Read(b); err != nil {
+        return "", err
+    }
+
+    domain := "localhost"
+    if at := strings.LastIndex(fromAddress, "@"); at >= 0 {
+        domain = fromAddress[at+1:]
+    }
+
+    return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain), nil
+}
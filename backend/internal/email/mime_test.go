@@ -0,0 +1,128 @@
+package email
+
+import (
+    "bytes"
+    "strings"
+    "testing"
+)
+
+func TestWriteHeader(t *testing.T) {
+    tests := []struct {
+        name    string
+        key     string
+        value   string
+        wantErr bool
+    }{
+        {"plain", "X-Kind", "digest", false},
+        {"empty value", "X-Kind", "", false},
+        {"crlf in value", "Subject", "hi\r\nBcc: evil@example.com", true},
+        {"lf in value", "Subject", "hi\nBcc: evil@example.com", true},
+        {"cr in value", "Subject", "hi\rBcc: evil@example.com", true},
+        {"empty key", "", "x", true},
+        {"colon in key", "Bcc: evil@example.com\r\nX", "x", true},
+        {"space in key", "X Kind", "x", true},
+        {"newline in key", "X-Kind\n", "x", true},
+    }
+
+    for _, tt := range tests {
+        var buf bytes.Buffer
+        err := writeHeader(&buf, tt.key, tt.value)
+
+        if tt.wantErr {
+            if err == nil {
+                t.Errorf("%s: writeHeader(%q, %q) succeeded, want error", tt.name, tt.key, tt.value)
+            }
+            if buf.Len() != 0 {
+                t.Errorf("%s: wrote %q on error", tt.name, buf.String())
+            }
+            continue
+        }
+
+        if err != nil {
+            t.Errorf("%s: writeHeader(%q, %q): %v", tt.name, tt.key, tt.value, err)
+            continue
+        }
+        if want := tt.key + ": " + tt.value + "\r\n"; buf.String() != want {
+            t.Errorf("%s: wrote %q, want %q", tt.name, buf.String(), want)
+        }
+    }
+}
+
+func TestBuildMIMERejectsInjection(t *testing.T) {
+    valid := Message{
+        From: "KULTUR <noreply@kulturtt.com>",
+        To:   "ana@example.com",
+    }
+
+    tests := []struct {
+        name string
+        edit func(m *Message)
+    }{
+        {"to with header", func(m *Message) { m.To = "ana@example.com\r\nBcc: evil@example.com" }},
+        {"from name with header", func(m *Message) { m.From = "KULTUR\r\nBcc: evil@example.com <noreply@kulturtt.com>" }},
+        {"invalid to", func(m *Message) { m.To = "not an address" }},
+        {"extra header value", func(m *Message) { m.Headers = map[string]string{"List-Unsubscribe": "<x>\r\nBcc: evil@example.com"} }},
+        {"extra header name", func(m *Message) { m.Headers = map[string]string{"Bcc: evil@example.com\r\nX": "x"} }},
+    }
+
+    for _, tt := range tests {
+        msg := valid
+        tt.edit(&msg)
+
+        if _, _, err := buildMIME(msg); err == nil {
+            t.Errorf("%s: buildMIME succeeded, want error", tt.name)
+        }
+    }
+}
+
+func TestBuildMIMEEncodesSubject(t *testing.T) {
+    raw, _, err := buildMIME(Message{
+        From:    "KULTUR <noreply@kulturtt.com>",
+        To:      "ana@example.com",
+        Subject: "Hi\r\nBcc: evil@example.com",
+        HTML:    "<p>Hi</p>",
+    })
+    if err != nil {
+        t.Fatalf("buildMIME: %v", err)
+    }
+
+    if strings.Contains(string(raw), "\r\nBcc:") {
+        t.Errorf("subject injected a header:\n%s", raw)
+    }
+}
+
+func TestBuildMIMEParts(t *testing.T) {
+    raw, messageID, err := buildMIME(Message{
+        From:    "KULTUR <noreply@kulturtt.com>",
+        To:      "ana@example.com",
+        Subject: "Hello",
+        HTML:    "<p>Hello</p>",
+        Text:    "Hello",
+        Headers: map[string]string{"List-Unsubscribe-Post": "List-Unsubscribe=One-Click"},
+    })
+    if err != nil {
+        t.Fatalf("buildMIME: %v", err)
+    }
+
+    if !strings.HasSuffix(messageID, "@kulturtt.com>") {
+        t.Errorf("Message-ID = %q, want the sender's domain", messageID)
+    }
+
+    msg := string(raw)
+    for _, want := range []string{
+        "To: <ana@example.com>\r\n",
+        "Message-ID: " + messageID + "\r\n",
+        "List-Unsubscribe-Post: List-Unsubscribe=One-Click\r\n",
+        "Content-Type: multipart/alternative;",
+    } {
+        if !strings.Contains(msg, want) {
+            t.Errorf("message is missing %q:\n%s", want, msg)
+        }
+    }
+
+    text := strings.Index(msg, `Content-Type: text/plain; charset="utf-8"`)
+    html := strings.Index(msg, `Content-Type: text/html; charset="utf-8"`)
+    if text < 0 || html < 0 || text > html {
+        t.Errorf("want the text part before the html part:\n%s", msg)
+    }
+}
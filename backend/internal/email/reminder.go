@@ -0,0 +1,52 @@
+package email
+
+import (
+    "context"
+    "fmt"
+)
+
+// ReminderData is the data for the "reminder" email.
+type ReminderData struct {
+    FestivalName   string
+    FestivalURL    string
+    When           string
+    UnsubscribeURL string
+}
+
+func init() {
+    Register("reminder", func(d ReminderData) (string, TemplateData) {
+        return fmt.Sprintf("🎭 %s is %s!", d.FestivalName, d.When), TemplateData{
+            PreviewText:    fmt.Sprintf("%s is %s - Don't miss it!", d.FestivalName, d.When),
+            Heading:        fmt.Sprintf("%s is %s!", d.FestivalName, d.When),
+            ButtonText:     "View Festival Guide",
+            ButtonURL:      d.FestivalURL,
+            UnsubscribeURL: d.UnsubscribeURL,
+        }
+    })
+}
+
+func (s *Service) SendFestivalReminder(ctx context.Context, toEmail, festivalName, festivalSlug, unsubscribeToken string, daysUntil int) error {
+    return s.Send(ctx, toEmail, "reminder", s.ReminderData(festivalName, festivalSlug, unsubscribeToken, daysUntil))
+}
+
+func (s *Service) ReminderData(festivalName, festivalSlug, unsubscribeToken string, daysUntil int) ReminderData {
+    return ReminderData{
+        FestivalName:   festivalName,
+        FestivalURL:    fmt.Sprintf("%s/festivals/%s", s.baseURL, festivalSlug),
+        When:           reminderWhen(daysUntil),
+        UnsubscribeURL: s.unsubscribeURL(unsubscribeToken),
+    }
+}
+
+func reminderWhen(daysUntil int) string {
+    switch daysUntil {
+    case 0:
+        return "Today"
+    case 1:
+        return "Tomorrow"
+    case 7:
+        return "In 1 week"
+    default:
+        return fmt.Sprintf("In %d days", daysUntil)
+    }
+}
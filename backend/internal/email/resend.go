@@ -0,0 +1,33 @@
+package email
+
+import (
+    "context"
+
+    "github.com/resend/resend-go/v2"
+)
+
+type ResendSender struct {
+    client *resend.Client
+}
+
+func NewResendSender(apiKey string) *ResendSender {
+    return &ResendSender{client: resend.NewClient(apiKey)}
+}
+
+func (r *ResendSender) Send(ctx context.Context, msg Message) (string, error) {
+    resp, err := r.client.Emails.SendWithOptions(ctx, &resend.SendEmailRequest{
+        From:    msg.From,
+        To:      []string{msg.To},
+        Subject: msg.Subject,
+        Html:    msg.HTML,
+        Text:    msg.Text,
+        Headers: msg.Headers,
+    }, &resend.SendEmailOptions{
+        IdempotencyKey: msg.IdempotencyKey,
+    })
+    if err != nil {
+        return "", err
+    }
+
+    return resp.Id, nil
+}
@@ -0,0 +1,63 @@
+package email
+
+import (
+    "context"
+    "fmt"
+)
+
+// Sender delivers a rendered message and returns the provider message ID.
+type Sender interface {
+    Send(ctx context.Context, msg Message) (string, error)
+}
+
+const (
+    BackendResend = "resend"
+    BackendSMTP   = "smtp"
+    BackendFile   = "file"
+    BackendMemory = "memory"
+    BackendNone   = "none"
+)
+
+type SenderConfig struct {
+    Backend      string
+    ResendAPIKey string
+    SMTPHost     string
+    SMTPPort     string
+    SMTPUsername string
+    SMTPPassword string
+    FileDir      string
+}
+
+// NewSender builds the Sender for cfg.Backend. An empty backend picks Resend
+// when an API key is set and disables sending otherwise. A nil Sender means
+// email is disabled.
+func NewSender(cfg SenderConfig) (Sender, error) {
+    backend := cfg.Backend
+    if backend == "" {
+        backend = BackendNone
+        if cfg.ResendAPIKey != "" {
+            backend = BackendResend
+        }
+    }
+
+    switch backend {
+    case BackendResend:
+        if cfg.ResendAPIKey == "" {
+            return nil, fmt.Errorf("RESEND_API_KEY is required for the %s backend", backend)
+        }
+        return NewResendSender(cfg.ResendAPIKey), nil
+    case BackendSMTP:
+        if cfg.SMTPHost == "" {
+            return nil, fmt.Errorf("SMTP_HOST is required for the %s backend", backend)
+        }
+        return NewSMTPSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword), nil
+    case BackendFile:
+        return NewFileSender(cfg.FileDir)
+    case BackendMemory:
+        return NewMemorySender(), nil
+    case BackendNone:
+        return nil, nil
+    default:
+        return nil, fmt.Errorf("unknown email backend %q", backend)
+    }
+}
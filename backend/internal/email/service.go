@@ -1,32 +1,45 @@
 package email
 
 import (
+    "context"
     "fmt"
-    "html/template"
     "time"
-
-    "github.com/resend/resend-go/v2"
 )
 
 type Service struct {
-    client    *resend.Client
+    sender    Sender
+    queue     Queue
     fromEmail string
     fromName  string
     baseURL   string
 }
 
+// Message is a fully rendered email, ready to be delivered.
+type Message struct {
+    // IdempotencyKey lets the provider drop duplicate deliveries on retry.
+    IdempotencyKey string
+    From           string
+    To             string
+    Subject        string
+    HTML           string
+    Text           string
+    Headers        map[string]string
+}
+
+// Queue stores messages for later delivery instead of sending them inline.
+type Queue interface {
+    Enqueue(ctx context.Context, msg Message) error
+}
+
 type Config struct {
-    APIKey    string
+    // Sender delivers rendered messages, nil disables sending.
+    Sender    Sender
     FromEmail string
     FromName  string
     BaseURL   string
 }
 
 func NewService(cfg Config) *Service {
-    if cfg.APIKey == "" {
-        return &Service{} // disabled mode
-    }
-
     fromName := cfg.FromName
     if fromName == "" {
         fromName = "KULTUR"
@@ -38,7 +51,7 @@ func NewService(cfg Config) *Service {
     }
 
     return &Service{
-        client:    resend.NewClient(cfg.APIKey),
+        sender:    cfg.Sender,
         fromEmail: fromEmail,
         fromName:  fromName,
         baseURL:   cfg.BaseURL,
@@ -46,280 +59,108 @@ func NewService(cfg Config) *Service {
 }
 
 func (s *Service) IsEnabled() bool {
-    return s.client != nil
+    return s.sender != nil
+}
+
+// UseQueue routes every Send* call through q. Queued messages are
+// delivered later with Deliver.
+func (s *Service) UseQueue(q Queue) {
+    s.queue = q
 }
 
 func (s *Service) from() string {
     return fmt.Sprintf("%s <%s>", s.fromName, s.fromEmail)
 }
 
-func (s *Service) SendConfirmation(toEmail, token string) error {
+func (s *Service) unsubscribeURL(token string) string {
+    return fmt.Sprintf("%s/api/unsubscribe/%s", s.baseURL, token)
+}
+
+// Send renders an email of the given kind and sends it, or queues it when a
+// Queue is in use.
+func (s *Service) Send(ctx context.Context, toEmail, kind string, data any) error {
     if !s.IsEnabled() {
         return nil
     }
 
-    confirmURL := fmt.Sprintf("%s/api/subscribe/confirm/%s", s.baseURL, token)
-
-    body := `<p style="margin: 0 0 16px 0;">
-        Thanks for subscribing to <strong>KULTUR</strong>! We're excited to share Trinidad & Tobago's rich cultural heritage with you.
-    </p>
-    <p style="margin: 0 0 16px 0;">
-        Please confirm your email address to start receiving updates about upcoming festivals, first-timer guides, and cultural events.
-    </p>
-    <p style="margin: 0; padding: 16px; background-color: #fef3c7; border-radius: 8px; border-left: 4px solid ` + ColorGold + `;">
-        <strong style="color: #92400e;">Note:</strong> This link expires in 24 hours. If you didn't subscribe, you can safely ignore this email.
-    </p>`
-
-    html, err := RenderTemplate(TemplateData{
-        PreviewText: "Confirm your email to discover Trinidad's cultural festivals",
-        Heading:     "Confirm Your Email",
-        Body:        template.HTML(body),
-        ButtonText:  "Confirm Email Address",
-        ButtonURL:   confirmURL,
-        Year:        time.Now().Year(),
-    })
+    msg, err := Render(toEmail, kind, data)
     if err != nil {
-        return fmt.Errorf("failed to render template: %w", err)
+        return err
     }
 
-    _, err = s.client.Emails.Send(&resend.SendEmailRequest{
-        From:    s.from(),
-        To:      []string{toEmail},
-        Subject: "Confirm your subscription to KULTUR",
-        Html:    html,
-    })
-
-    return err
+    return s.send(ctx, msg)
 }
 
-func (s *Service) SendWelcome(toEmail, unsubscribeToken string) error {
-    if !s.IsEnabled() {
-        return nil
+// Render builds a message of the given kind with HTML and plain text parts.
+// Emails with an unsubscribe link also get RFC 8058 one-click unsubscribe
+// headers.
+func Render(toEmail, kind string, data any) (Message, error) {
+    k, ok := kinds[kind]
+    if !ok {
+        return Message{}, fmt.Errorf("unknown email kind %q", kind)
     }
 
-    unsubURL := fmt.Sprintf("%s/api/unsubscribe/%s", s.baseURL, unsubscribeToken)
-    calendarURL := fmt.Sprintf("%s/festivals", s.baseURL)
-
-    body := fmt.Sprintf(`<p style="margin: 0 0 16px 0;">
-        Welcome to the KULTUR community! Your subscription is now confirmed.
-    </p>
-
-    <p style="margin: 0 0 24px 0;">
-        You'll receive updates about:
-    </p>
-
-    <table role="presentation" cellspacing="0" cellpadding="0" border="0" style="margin: 0 0 24px 0;">
-        <tr>
-            <td style="padding: 12px 16px; background-color: #fef2f2; border-radius: 8px; margin-bottom: 8px;">
-                <table role="presentation" cellspacing="0" cellpadding="0" border="0">
-                    <tr>
-                        <td style="padding-right: 12px; vertical-align: top;">
-                            <span style="font-size: 20px;">🎭</span>
-                        </td>
-                        <td>
-                            <strong style="color: %s;">Festival Announcements</strong><br>
-                            <span style="font-size: 14px; color: #6b7280;">Know when festivals are happening</span>
-                        </td>
-                    </tr>
-                </table>
-            </td>
-        </tr>
-        <tr><td style="height: 8px;"></td></tr>
-        <tr>
-            <td style="padding: 12px 16px; background-color: #fef9c3; border-radius: 8px; margin-bottom: 8px;">
-                <table role="presentation" cellspacing="0" cellpadding="0" border="0">
-                    <tr>
-                        <td style="padding-right: 12px; vertical-align: top;">
-                            <span style="font-size: 20px;">📖</span>
-                        </td>
-                        <td>
-                            <strong style="color: %s;">First-Timer Guides</strong><br>
-                            <span style="font-size: 14px; color: #6b7280;">What to expect and how to participate</span>
-                        </td>
-                    </tr>
-                </table>
-            </td>
-        </tr>
-        <tr><td style="height: 8px;"></td></tr>
-        <tr>
-            <td style="padding: 12px 16px; background-color: #ecfdf5; border-radius: 8px;">
-                <table role="presentation" cellspacing="0" cellpadding="0" border="0">
-                    <tr>
-                        <td style="padding-right: 12px; vertical-align: top;">
-                            <span style="font-size: 20px;">🗓️</span>
-                        </td>
-                        <td>
-                            <strong style="color: %s;">Weekly Digest</strong><br>
-                            <span style="font-size: 14px; color: #6b7280;">Cultural events happening this week</span>
-                        </td>
-                    </tr>
-                </table>
-            </td>
-        </tr>
-    </table>
-
-    <p style="margin: 0;">
-        Ready to explore? Check out our <a href="%s" style="color: %s; font-weight: 600;">festival calendar</a> to see what's coming up.
-    </p>`, ColorRed, ColorGold, "#059669", calendarURL, ColorRed)
-
-    html, err := RenderTemplate(TemplateData{
-        PreviewText:    "Welcome to KULTUR! Start exploring Trinidad's festivals",
-        Heading:        "You're All Set! 🎉",
-        Body:           template.HTML(body),
-        ButtonText:     "Explore Festivals",
-        ButtonURL:      calendarURL,
-        FooterText:     "Thank you for joining us in celebrating Trinidad & Tobago's cultural heritage.",
-        UnsubscribeURL: unsubURL,
-        Year:           time.Now().Year(),
-    })
+    subject, layout, err := k.layout(data)
     if err != nil {
-        return fmt.Errorf("failed to render template: %w", err)
+        return Message{}, err
     }
 
-    _, err = s.client.Emails.Send(&resend.SendEmailRequest{
-        From:    s.from(),
-        To:      []string{toEmail},
-        Subject: "Welcome to KULTUR! 🎭",
-        Html:    html,
-    })
-
-    return err
-}
-
-type FestivalDigestItem struct {
-    Name      string
-    Slug      string
-    Date      string
-    Heritage  string
-    Region    string
-}
-
-func (s *Service) SendWeeklyDigest(toEmail string, festivals []FestivalDigestItem, unsubscribeToken string) error {
-    if !s.IsEnabled() {
-        return nil
+    layout.Body, err = RenderBody(kind, data)
+    if err != nil {
+        return Message{}, fmt.Errorf("failed to render %s body: %w", kind, err)
     }
 
-    if len(festivals) == 0 {
-        return nil // Don't send empty digest
+    if layout.Year == 0 {
+        layout.Year = time.Now().Year()
     }
 
-    unsubURL := fmt.Sprintf("%s/api/unsubscribe/%s", s.baseURL, unsubscribeToken)
-    calendarURL := fmt.Sprintf("%s/festivals", s.baseURL)
-
-    var festivalListHTML string
-    for _, f := range festivals {
-        festivalURL := fmt.Sprintf("%s/festivals/%s", s.baseURL, f.Slug)
-        festivalListHTML += fmt.Sprintf(`
-        <tr>
-            <td style="padding: 16px; background-color: #f9fafb; border-radius: 8px; margin-bottom: 8px;">
-                <table role="presentation" cellspacing="0" cellpadding="0" border="0" width="100%%">
-                    <tr>
-                        <td>
-                            <a href="%s" style="font-size: 16px; font-weight: 600; color: %s; text-decoration: none;">%s</a>
-                            <p style="margin: 4px 0 0 0; font-size: 14px; color: #6b7280;">
-                                %s · %s · %s
-                            </p>
-                        </td>
-                    </tr>
-                </table>
-            </td>
-        </tr>
-        <tr><td style="height: 8px;"></td></tr>`, festivalURL, ColorRed, f.Name, f.Date, f.Heritage, f.Region)
+    html, err := RenderTemplate(layout)
+    if err != nil {
+        return Message{}, fmt.Errorf("failed to render template: %w", err)
     }
 
-    body := fmt.Sprintf(`<p style="margin: 0 0 16px 0;">
-        Here's what's happening in Trinidad & Tobago's cultural scene this week.
-    </p>
+    text, err := RenderText(layout)
+    if err != nil {
+        return Message{}, fmt.Errorf("failed to render text: %w", err)
+    }
 
-    <p style="margin: 0 0 24px 0; font-size: 18px; font-weight: 600; color: %s;">
-        Upcoming Festivals
-    </p>
+    msg := Message{
+        To:      toEmail,
+        Subject: subject,
+        HTML:    html,
+        Text:    text,
+    }
 
-    <table role="presentation" cellspacing="0" cellpadding="0" border="0" width="100%%" style="margin: 0 0 24px 0;">
-        %s
-    </table>
+    if layout.UnsubscribeURL != "" {
+        msg.Headers = map[string]string{
+            "List-Unsubscribe":      "<" + layout.UnsubscribeURL + ">",
+            "List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+        }
+    }
 
-    <p style="margin: 0;">
-        Want to learn more? Check out our <a href="%s" style="color: %s; font-weight: 600;">festival guides</a> for tips on what to expect and how to participate.
-    </p>`, ColorBlack, festivalListHTML, calendarURL, ColorRed)
+    return msg, nil
+}
 
-    html, err := RenderTemplate(TemplateData{
-        PreviewText:    fmt.Sprintf("%d festivals coming up this week in T&T", len(festivals)),
-        Heading:        "This Week in T&T",
-        Body:           template.HTML(body),
-        ButtonText:     "View Full Calendar",
-        ButtonURL:      calendarURL,
-        FooterText:     "You're receiving this because you subscribed to KULTUR festival updates.",
-        UnsubscribeURL: unsubURL,
-        Year:           time.Now().Year(),
-    })
-    if err != nil {
-        return fmt.Errorf("failed to render template: %w", err)
+func (s *Service) send(ctx context.Context, msg Message) error {
+    if s.queue != nil {
+        return s.queue.Enqueue(ctx, msg)
     }
 
-    _, err = s.client.Emails.Send(&resend.SendEmailRequest{
-        From:    s.from(),
-        To:      []string{toEmail},
-        Subject: fmt.Sprintf("This Week in T&T: %d Festivals Coming Up", len(festivals)),
-        Html:    html,
-    })
+    _, err := s.Deliver(ctx, msg)
 
     return err
 }
 
-func (s *Service) SendFestivalReminder(toEmail, festivalName, festivalSlug, unsubscribeToken string, daysUntil int) error {
+// Deliver sends msg through the configured Sender right away and returns the
+// provider message ID.
+func (s *Service) Deliver(ctx context.Context, msg Message) (string, error) {
     if !s.IsEnabled() {
-        return nil
-    }
-
-    unsubURL := fmt.Sprintf("%s/api/unsubscribe/%s", s.baseURL, unsubscribeToken)
-    festivalURL := fmt.Sprintf("%s/festivals/%s", s.baseURL, festivalSlug)
-
-    var timeText string
-    switch daysUntil {
-    case 0:
-        timeText = "Today"
-    case 1:
-        timeText = "Tomorrow"
-    case 7:
-        timeText = "In 1 week"
-    default:
-        timeText = fmt.Sprintf("In %d days", daysUntil)
+        return "", nil
     }
 
-    body := fmt.Sprintf(`<p style="margin: 0 0 16px 0;">
-        <strong>%s</strong> is coming up <strong>%s</strong>!
-    </p>
-
-    <p style="margin: 0 0 24px 0;">
-        Don't miss this opportunity to experience one of Trinidad & Tobago's cultural treasures. Check out our first-timer's guide to know what to expect.
-    </p>
-
-    <div style="padding: 20px; background-color: #f9fafb; border-radius: 8px; border: 1px solid %s;">
-        <p style="margin: 0; font-size: 14px; color: #6b7280;">
-            📍 View the complete guide including what to wear, how to participate, and practical tips.
-        </p>
-    </div>`, festivalName, timeText, ColorBorder)
-
-    html, err := RenderTemplate(TemplateData{
-        PreviewText:    fmt.Sprintf("%s is %s - Don't miss it!", festivalName, timeText),
-        Heading:        fmt.Sprintf("%s is %s!", festivalName, timeText),
-        Body:           template.HTML(body),
-        ButtonText:     "View Festival Guide",
-        ButtonURL:      festivalURL,
-        UnsubscribeURL: unsubURL,
-        Year:           time.Now().Year(),
-    })
-    if err != nil {
-        return fmt.Errorf("failed to render template: %w", err)
+    if msg.From == "" {
+        msg.From = s.from()
     }
 
-    _, err = s.client.Emails.Send(&resend.SendEmailRequest{
-        From:    s.from(),
-        To:      []string{toEmail},
-        Subject: fmt.Sprintf("🎭 %s is %s!", festivalName, timeText),
-        Html:    html,
-    })
-
-    return err
+    return s.sender.Send(ctx, msg)
 }
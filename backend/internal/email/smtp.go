@@ -0,0 +1,53 @@
+package email
+
+import (
+    "context"
+    "fmt"
+    "net"
+    "net/mail"
+    "net/smtp"
+)
+
+// SMTPSender delivers over plain SMTP, e.g. to Mailpit or MailHog locally.
+type SMTPSender struct {
+    addr     string
+    host     string
+    username string
+    password string
+}
+
+func NewSMTPSender(host, port, username, password string) *SMTPSender {
+    if port == "" {
+        port = "1025"
+    }
+
+    return &SMTPSender{
+        addr:     net.JoinHostPort(host, port),
+        host:     host,
+        username: username,
+        password: password,
+    }
+}
+
+func (s *SMTPSender) Send(ctx context.Context, msg Message) (string, error) {
+    from, err := mail.ParseAddress(msg.From)
+    if err != nil {
+        return "", fmt.Errorf("invalid from address: %w", err)
+    }
+
+    raw, messageID, err := buildMIME(msg)
+    if err != nil {
+        return "", err
+    }
+
+    var auth smtp.Auth
+    if s.username != "" {
+        auth = smtp.PlainAuth("", s.username, s.password, s.host)
+    }
+
+    if err := smtp.SendMail(s.addr, auth, from.Address, []string{msg.To}, raw); err != nil {
+        return "", err
+    }
+
+    return messageID, nil
+}
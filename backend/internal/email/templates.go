@@ -2,6 +2,8 @@ package email
 
 import (
     "bytes"
+    "embed"
+    "fmt"
     "html/template"
 )
 
@@ -15,6 +17,17 @@ const (
     ColorBorder  = "#e5e7eb"
 )
 
+var colors = map[string]string{
+    "red":      ColorRed,
+    "red-dark": ColorRedDark,
+    "black":    ColorBlack,
+    "white":    ColorWhite,
+    "gold":     ColorGold,
+    "gray":     ColorGray,
+    "border":   ColorBorder,
+}
+
+// TemplateData fills in the base template around an email body.
 type TemplateData struct {
     PreviewText    string
     LogoURL        string
@@ -153,6 +166,35 @@ const baseTemplate = `<!DOCTYPE html>
 
 var tmpl = template.Must(template.New("email").Parse(baseTemplate))
 
+// bodies holds one template per email kind, named after its file.
+//
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var bodies = template.Must(template.New("bodies").Funcs(template.FuncMap{
+    "color": color,
+}).ParseFS(templateFS, "templates/*.html"))
+
+func color(name string) (template.CSS, error) {
+    c, ok := colors[name]
+    if !ok {
+        return "", fmt.Errorf("unknown color %q", name)
+    }
+
+    return template.CSS(c), nil
+}
+
+// RenderBody executes the body template for an email kind. Its output is
+// already escaped, so it can be placed in TemplateData.Body as is.
+func RenderBody(kind string, data any) (template.HTML, error) {
+    var buf bytes.Buffer
+    if err := bodies.ExecuteTemplate(&buf, kind+".html", data); err != nil {
+        return "", err
+    }
+
+    return template.HTML(buf.String()), nil
+}
+
 func RenderTemplate(data TemplateData) (string, error) {
     var buf bytes.Buffer
     if err := tmpl.Execute(&buf, data); err != nil {
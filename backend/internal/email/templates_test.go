@@ -0,0 +1,111 @@
+package email
+
+import (
+    "slices"
+    "strings"
+    "testing"
+)
+
+func TestRenderEscapesDigest(t *testing.T) {
+    msg, err := Render("ana@example.com", "digest", DigestData{
+        Festivals: []FestivalDigestItem{{
+            Name:     `<script>alert("hi")</script>`,
+            Date:     "Mar 3",
+            Heritage: "Mixed Heritage",
+            Region:   "Port of Spain & San Fernando",
+            URL:      "https://kultur.example/festivals/carnival",
+        }},
+        CalendarURL:    "https://kultur.example/festivals",
+        UnsubscribeURL: "https://kultur.example/api/unsubscribe/token",
+    })
+    if err != nil {
+        t.Fatalf("Render: %v", err)
+    }
+
+    for _, want := range []string{
+        "&lt;script&gt;alert(&#34;hi&#34;)&lt;/script&gt;",
+        "Port of Spain &amp; San Fernando",
+        "This Week in T&amp;T",
+    } {
+        if !strings.Contains(msg.HTML, want) {
+            t.Errorf("HTML is missing %q", want)
+        }
+    }
+    for _, bad := range []string{"<script>", "Spain & San"} {
+        if strings.Contains(msg.HTML, bad) {
+            t.Errorf("HTML contains unescaped %q", bad)
+        }
+    }
+
+    // plain text is not markup, so it carries the characters as written
+    for _, want := range []string{
+        `<script>alert("hi")</script> (https://kultur.example/festivals/carnival)`,
+        "Mar 3 · Mixed Heritage · Port of Spain & San Fernando",
+        "Unsubscribe: https://kultur.example/api/unsubscribe/token",
+    } {
+        if !strings.Contains(msg.Text, want) {
+            t.Errorf("Text is missing %q:\n%s", want, msg.Text)
+        }
+    }
+    if strings.Contains(msg.Text, "&amp;") || strings.Contains(msg.Text, "&lt;") {
+        t.Errorf("Text contains HTML entities:\n%s", msg.Text)
+    }
+
+    if msg.Subject != "This Week in T&T: 1 Festivals Coming Up" {
+        t.Errorf("Subject = %q", msg.Subject)
+    }
+}
+
+func TestRenderRejectsWrongData(t *testing.T) {
+    if _, err := Render("ana@example.com", "digest", WelcomeData{}); err == nil {
+        t.Error("Render with the wrong data type succeeded, want error")
+    }
+
+    if _, err := Render("ana@example.com", "no-such-kind", nil); err == nil {
+        t.Error("Render with an unknown kind succeeded, want error")
+    }
+}
+
+func TestRegisterPanics(t *testing.T) {
+    tests := []struct {
+        name string
+        kind string
+    }{
+        {"duplicate kind", "digest"},
+        {"no template", "no-such-kind"},
+    }
+
+    for _, tt := range tests {
+        func() {
+            defer func() {
+                if recover() == nil {
+                    t.Errorf("%s: Register(%q) did not panic", tt.name, tt.kind)
+                }
+            }()
+
+            Register(tt.kind, func(d WelcomeData) (string, TemplateData) {
+                return "replaced", TemplateData{}
+            })
+        }()
+    }
+
+    // the original registration still renders
+    msg, err := Render("ana@example.com", "digest", DigestData{Festivals: []FestivalDigestItem{{Name: "Carnival"}}})
+    if err != nil {
+        t.Fatalf("Render: %v", err)
+    }
+    if msg.Subject == "replaced" {
+        t.Error("duplicate Register replaced the digest kind")
+    }
+
+    if slices.Contains(Kinds(), "no-such-kind") {
+        t.Error("a kind without a template was registered")
+    }
+}
+
+func TestKinds(t *testing.T) {
+    want := []string{"confirmation", "digest", "memory-approved", "memory-rejected", "reminder", "welcome"}
+    if got := Kinds(); !slices.Equal(got, want) {
+        t.Errorf("Kinds() = %v, want %v", got, want)
+    }
+}
@@ -0,0 +1,130 @@
+package email
+
+import (
+    "bytes"
+    "fmt"
+    "regexp"
+    "strings"
+
+    "golang.org/x/net/html"
+)
+
+// RenderText builds the text/plain alternative for data. The body is
+// converted from its HTML so both parts always carry the same copy.
+func RenderText(data TemplateData) (string, error) {
+    body, err := htmlToText(string(data.Body))
+    if err != nil {
+        return "", err
+    }
+
+    var b strings.Builder
+
+    b.WriteString(data.Heading)
+    b.WriteString("\n\n")
+    b.WriteString(body)
+    b.WriteString("\n")
+
+    if data.ButtonText != "" {
+        fmt.Fprintf(&b, "\n%s: %s\n", data.ButtonText, data.ButtonURL)
+    }
+
+    if data.FooterText != "" {
+        fmt.Fprintf(&b, "\n%s\n", data.FooterText)
+    }
+
+    b.WriteString("\n--\nKULTUR · Your guide to Trinidad's cultural festivals\n")
+
+    if data.UnsubscribeURL != "" {
+        fmt.Fprintf(&b, "Unsubscribe: %s\n", data.UnsubscribeURL)
+    }
+
+    return b.String(), nil
+}
+
+var (
+    blockTags = map[string]bool{
+        "p": true, "div": true, "table": true, "tr": true, "br": true, "li": true,
+        "h1": true, "h2": true, "h3": true, "h4": true, "ul": true, "ol": true,
+    }
+    spaces     = regexp.MustCompile(`[ \t\r\n]+`)
+    blankLines = regexp.MustCompile(`\n[ \t]*\n(\s*\n)+`)
+)
+
+// htmlToText flattens an HTML fragment into readable plain text. Block
+// elements become line breaks and links keep their URL in parentheses.
+func htmlToText(fragment string) (string, error) {
+    nodes, err := html.ParseFragment(strings.NewReader(fragment), nil)
+    if err != nil {
+        return "", err
+    }
+
+    var buf bytes.Buffer
+    for _, n := range nodes {
+        writeText(&buf, n)
+    }
+
+    text := blankLines.ReplaceAllString(buf.String(), "\n\n")
+
+    lines := strings.Split(text, "\n")
+    for i, line := range lines {
+        lines[i] = strings.TrimSpace(line)
+    }
+
+    return strings.TrimSpace(strings.Join(lines, "\n")), nil
+}
+
+func writeText(buf *bytes.Buffer, n *html.Node) {
+    switch n.Type {
+    case html.TextNode:
+        buf.WriteString(spaces.ReplaceAllString(n.Data, " "))
+        return
+    case html.ElementNode:
+        switch n.Data {
+        case "style", "script", "head", "title":
+            return
+        case "a":
+            var inner bytes.Buffer
+            for c := n.FirstChild; c != nil; c = c.NextSibling {
+                writeText(&inner, c)
+            }
+
+            label := strings.TrimSpace(inner.String())
+            href := attr(n, "href")
+
+            switch {
+            case href == "" || href == label:
+                buf.WriteString(label)
+            case label == "":
+                buf.WriteString(href)
+            default:
+                fmt.Fprintf(buf, "%s (%s)", label, href)
+            }
+            return
+        }
+    }
+
+    block := n.Type == html.ElementNode && blockTags[n.Data]
+    if block {
+        buf.WriteString("\n")
+    }
+
+    for c := n.FirstChild; c != nil; c = c.NextSibling {
+        writeText(buf, c)
+    }
+
+    if block && n.Data == "p" {
+        buf.WriteString("\n\n")
+    } else if block {
+        buf.WriteString("\n")
+    }
+}
+
+func attr(n *html.Node, key string) string {
+    for _, a := range n.Attr {
+        if a.Key == key {
+            return a.Val
+        }
+    }
+
+    return ""
+}
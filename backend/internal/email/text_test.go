@@ -0,0 +1,34 @@
+package email
+
+import "testing"
+
+func TestHTMLToText(t *testing.T) {
+    tests := []struct {
+        name string
+        html string
+        want string
+    }{
+        {"plain", "Hello", "Hello"},
+        {"collapses whitespace", "Hello \n\t  there", "Hello there"},
+        {"paragraphs", "<p>One</p><p>Two</p>", "One\n\nTwo"},
+        {"link", `<a href="https://kultur.example/festivals">festival guides</a>`, "festival guides (https://kultur.example/festivals)"},
+        {"link with url as label", `<a href="https://kultur.example">https://kultur.example</a>`, "https://kultur.example"},
+        {"link without label", `<a href="https://kultur.example"></a>`, "https://kultur.example"},
+        {"link without href", `<a>festival guides</a>`, "festival guides"},
+        {"entities", "Trinidad &amp; Tobago &lt;3", "Trinidad & Tobago <3"},
+        {"drops style and script", "<style>p { color: red; }</style><script>alert(1)</script><p>Hi</p>", "Hi"},
+        {"trims indented lines", "<div>\n    One\n</div>\n<div>\n    Two\n</div>", "One\n\nTwo"},
+        {"table cells", "<table><tr><td>Carnival</td></tr><tr><td>Divali</td></tr></table>", "Carnival\n\nDivali"},
+    }
+
+    for _, tt := range tests {
+        got, err := htmlToText(tt.html)
+        if err != nil {
+            t.Errorf("%s: htmlToText: %v", tt.name, err)
+            continue
+        }
+        if got != tt.want {
+            t.Errorf("%s: htmlToText(%q) = %q, want %q", tt.name, tt.html, got, tt.want)
+        }
+    }
+}
@@ -0,0 +1,36 @@
+package email
+
+import (
+    "context"
+    "fmt"
+)
+
+// WelcomeData is the data for the "welcome" email.
+type WelcomeData struct {
+    CalendarURL    string
+    UnsubscribeURL string
+}
+
+func init() {
+    Register("welcome", func(d WelcomeData) (string, TemplateData) {
+        return "Welcome to KULTUR! 🎭", TemplateData{
+            PreviewText:    "Welcome to KULTUR! Start exploring Trinidad's festivals",
+            Heading:        "You're All Set! 🎉",
+            ButtonText:     "Explore Festivals",
+            ButtonURL:      d.CalendarURL,
+            FooterText:     "Thank you for joining us in celebrating Trinidad & Tobago's cultural heritage.",
+            UnsubscribeURL: d.UnsubscribeURL,
+        }
+    })
+}
+
+func (s *Service) SendWelcome(ctx context.Context, toEmail, unsubscribeToken string) error {
+    return s.Send(ctx, toEmail, "welcome", s.WelcomeData(unsubscribeToken))
+}
+
+func (s *Service) WelcomeData(unsubscribeToken string) WelcomeData {
+    return WelcomeData{
+        CalendarURL:    fmt.Sprintf("%s/festivals", s.baseURL),
+        UnsubscribeURL: s.unsubscribeURL(unsubscribeToken),
+    }
+}
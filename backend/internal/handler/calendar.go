@@ -0,0 +1,100 @@
+package handler
+
+import (
+    "errors"
+    "fmt"
+    "net/http"
+    "strings"
+    "time"
+
+    "github.com/aidantrabs/kultur/backend/internal/ical"
+    "github.com/aidantrabs/kultur/backend/internal/service"
+    "github.com/labstack/echo/v4"
+)
+
+func (h *Handler) FestivalsCalendarFeed(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    cal, err := h.calendars.Feed(ctx, service.CalendarFeedParams{
+        Region:   c.QueryParam("region"),
+        Heritage: c.QueryParam("heritage"),
+    })
+    if err != nil {
+        return echo.NewHTTPError(http.StatusInternalServerError, "failed to build calendar")
+    }
+
+    return writeCalendar(c, "kultur-festivals.ics", "public, max-age=3600", cal)
+}
+
+func (h *Handler) FestivalCalendarFeed(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    slug := c.Param("slug")
+
+    cal, err := h.calendars.FestivalFeed(ctx, slug)
+    if errors.Is(err, service.ErrFestivalNotFound) {
+        return echo.NewHTTPError(http.StatusNotFound, "festival not found")
+    }
+    if err != nil {
+        return echo.NewHTTPError(http.StatusInternalServerError, "failed to build calendar")
+    }
+
+    return writeCalendar(c, slug+".ics", "public, max-age=3600", cal)
+}
+
+// PersonalCalendarFeed serves a subscriber's private feed. The token is the
+// subscription's calendar token, with or without a .ics suffix.
+func (h *Handler) PersonalCalendarFeed(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+    cal, err := h.calendars.PersonalFeed(ctx, token)
+    if errors.Is(err, service.ErrInvalidToken) {
+        return echo.NewHTTPError(http.StatusNotFound, "invalid calendar token")
+    }
+    if err != nil {
+        return echo.NewHTTPError(http.StatusInternalServerError, "failed to build calendar")
+    }
+
+    return writeCalendar(c, "my-kultur-festivals.ics", "private, max-age=3600", cal)
+}
+
+func (h *Handler) GetCalendarURL(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    token, err := h.subscriptions.GetCalendarToken(ctx, c.Param("token"))
+    if errors.Is(err, service.ErrInvalidToken) {
+        return echo.NewHTTPError(http.StatusNotFound, "invalid subscription token")
+    }
+    if err != nil {
+        return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch calendar url")
+    }
+
+    return c.JSON(http.StatusOK, map[string]string{
+        "calendarUrl": h.calendars.PersonalURL(token),
+    })
+}
+
+func (h *Handler) RotateCalendarToken(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    token, err := h.subscriptions.RotateCalendarToken(ctx, c.Param("token"))
+    if errors.Is(err, service.ErrInvalidToken) {
+        return echo.NewHTTPError(http.StatusNotFound, "invalid subscription token")
+    }
+    if err != nil {
+        return echo.NewHTTPError(http.StatusInternalServerError, "failed to rotate calendar token")
+    }
+
+    return c.JSON(http.StatusOK, map[string]string{
+        "calendarUrl": h.calendars.PersonalURL(token),
+    })
+}
+
+func writeCalendar(c echo.Context, filename, cacheControl string, cal ical.Calendar) error {
+    c.Response().Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, filename))
+    c.Response().Header().Set("Cache-Control", cacheControl)
+
+    return c.Blob(http.StatusOK, "text/calendar; charset=utf-8", cal.Encode(time.Now()))
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+    "errors"
+    "net/http"
+    "strconv"
+
+    "github.com/aidantrabs/kultur/backend/internal/service"
+    "github.com/google/uuid"
+    "github.com/jackc/pgx/v5/pgtype"
+    "github.com/labstack/echo/v4"
+)
+
+type EmailPreviewResponse struct {
+    Type    string            `json:"type"`
+    To      string            `json:"to"`
+    Subject string            `json:"subject"`
+    HTML    string            `json:"html"`
+    Text    string            `json:"text"`
+    Headers map[string]string `json:"headers,omitempty"`
+}
+
+func (h *Handler) PreviewEmail(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    emailType := c.Param("type")
+
+    params := service.PreviewParams{
+        Slug: c.QueryParam("slug"),
+    }
+
+    if raw := c.QueryParam("subscriber_id"); raw != "" {
+        id, err := uuid.Parse(raw)
+        if err != nil {
+            return echo.NewHTTPError(http.StatusBadRequest, "invalid subscriber id")
+        }
+        params.SubscriberID = pgtype.UUID{Bytes: id, Valid: true}
+    }
+
+    if raw := c.QueryParam("days_until"); raw != "" {
+        days, err := strconv.Atoi(raw)
+        if err != nil || days < 0 || days > service.MaxReminderDays {
+            return echo.NewHTTPError(http.StatusBadRequest, "invalid days_until")
+        }
+        params.DaysUntil = &days
+    }
+
+    msg, err := h.previews.Render(ctx, emailType, params)
+    if errors.Is(err, service.ErrUnknownEmailType) {
+        return echo.NewHTTPError(http.StatusNotFound, "unknown email type")
+    }
+    if errors.Is(err, service.ErrFestivalNotFound) {
+        return echo.NewHTTPError(http.StatusNotFound, "festival not found")
+    }
+    if errors.Is(err, service.ErrSubscriptionNotFound) {
+        return echo.NewHTTPError(http.StatusNotFound, "subscription not found")
+    }
+    if err != nil {
+        return echo.NewHTTPError(http.StatusInternalServerError, "failed to render email")
+    }
+
+    return c.JSON(http.StatusOK, EmailPreviewResponse{
+        Type:    emailType,
+        To:      msg.To,
+        Subject: msg.Subject,
+        HTML:    msg.HTML,
+        Text:    msg.Text,
+        Headers: msg.Headers,
+    })
+}
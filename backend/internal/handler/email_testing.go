@@ -12,6 +12,8 @@ type TestEmailRequest struct {
 }
 
 func (h *Handler) TestWelcomeEmail(c echo.Context) error {
+    ctx := c.Request().Context()
+
     var req TestEmailRequest
     if err := c.Bind(&req); err != nil {
         return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
@@ -21,17 +23,19 @@ func (h *Handler) TestWelcomeEmail(c echo.Context) error {
         return echo.NewHTTPError(http.StatusBadRequest, "email is required")
     }
 
-    if err := h.email.SendWelcome(req.Email, "test-unsubscribe-token"); err != nil {
+    if err := h.email.SendWelcome(ctx, req.Email, "test-unsubscribe-token"); err != nil {
         return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
     }
 
     return c.JSON(http.StatusOK, map[string]string{
-        "message": "welcome email sent",
+        "message": "welcome email queued",
         "to":      req.Email,
     })
 }
 
 func (h *Handler) TestFestivalReminder(c echo.Context) error {
+    ctx := c.Request().Context()
+
     var req TestEmailRequest
     if err := c.Bind(&req); err != nil {
         return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
@@ -41,17 +45,19 @@ func (h *Handler) TestFestivalReminder(c echo.Context) error {
         return echo.NewHTTPError(http.StatusBadRequest, "email is required")
     }
 
-    if err := h.email.SendFestivalReminder(req.Email, "Trinidad Carnival", "carnival", "test-unsubscribe-token", 7); err != nil {
+    if err := h.email.SendFestivalReminder(ctx, req.Email, "Trinidad Carnival", "carnival", "test-unsubscribe-token", 7); err != nil {
         return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
     }
 
     return c.JSON(http.StatusOK, map[string]string{
-        "message": "festival reminder email sent",
+        "message": "festival reminder email queued",
         "to":      req.Email,
     })
 }
 
 func (h *Handler) TestWeeklyDigest(c echo.Context) error {
+    ctx := c.Request().Context()
+
     var req TestEmailRequest
     if err := c.Bind(&req); err != nil {
         return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
@@ -85,12 +91,12 @@ func (h *Handler) TestWeeklyDigest(c echo.Context) error {
         },
     }
 
-    if err := h.email.SendWeeklyDigest(req.Email, testFestivals, "test-unsubscribe-token"); err != nil {
+    if err := h.email.SendWeeklyDigest(ctx, req.Email, testFestivals, "test-unsubscribe-token"); err != nil {
         return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
     }
 
     return c.JSON(http.StatusOK, map[string]string{
-        "message": "weekly digest email sent",
+        "message": "weekly digest email queued",
         "to":      req.Email,
     })
 }
@@ -0,0 +1,160 @@
+package handler
+
+import (
+    "errors"
+    "net/http"
+
+    "github.com/aidantrabs/kultur/backend/internal/db"
+    "github.com/aidantrabs/kultur/backend/internal/service"
+    "github.com/google/uuid"
+    "github.com/jackc/pgx/v5/pgtype"
+    "github.com/labstack/echo/v4"
+)
+
+type AddGalleryImageRequest struct {
+    URL     string `json:"url"`
+    Caption string `json:"caption"`
+    Credit  string `json:"credit"`
+    Alt     string `json:"alt"`
+}
+
+type AddVideoEmbedRequest struct {
+    Provider string `json:"provider"`
+    VideoID  string `json:"video_id"`
+    URL      string `json:"url"`
+    Title    string `json:"title"`
+}
+
+type ReorderMediaRequest struct {
+    IDs []string `json:"ids"`
+}
+
+func (h *Handler) AddGalleryImage(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    id, err := festivalID(c)
+    if err != nil {
+        return err
+    }
+
+    var req AddGalleryImageRequest
+    if err := c.Bind(&req); err != nil {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+    }
+
+    festival, err := h.festivals.AddGalleryImage(ctx, id, service.GalleryImageParams{
+        URL:     req.URL,
+        Caption: req.Caption,
+        Credit:  req.Credit,
+        Alt:     req.Alt,
+    })
+    return mediaResponse(c, http.StatusCreated, festival, err)
+}
+
+func (h *Handler) ReorderGallery(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    id, err := festivalID(c)
+    if err != nil {
+        return err
+    }
+
+    var req ReorderMediaRequest
+    if err := c.Bind(&req); err != nil {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+    }
+
+    festival, err := h.festivals.ReorderGallery(ctx, id, req.IDs)
+    return mediaResponse(c, http.StatusOK, festival, err)
+}
+
+func (h *Handler) RemoveGalleryImage(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    id, err := festivalID(c)
+    if err != nil {
+        return err
+    }
+
+    festival, err := h.festivals.RemoveGalleryImage(ctx, id, c.Param("item_id"))
+    return mediaResponse(c, http.StatusOK, festival, err)
+}
+
+func (h *Handler) AddVideoEmbed(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    id, err := festivalID(c)
+    if err != nil {
+        return err
+    }
+
+    var req AddVideoEmbedRequest
+    if err := c.Bind(&req); err != nil {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+    }
+
+    festival, err := h.festivals.AddVideoEmbed(ctx, id, service.VideoEmbedParams{
+        Provider: req.Provider,
+        VideoID:  req.VideoID,
+        URL:      req.URL,
+        Title:    req.Title,
+    })
+    return mediaResponse(c, http.StatusCreated, festival, err)
+}
+
+func (h *Handler) ReorderVideos(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    id, err := festivalID(c)
+    if err != nil {
+        return err
+    }
+
+    var req ReorderMediaRequest
+    if err := c.Bind(&req); err != nil {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+    }
+
+    festival, err := h.festivals.ReorderVideos(ctx, id, req.IDs)
+    return mediaResponse(c, http.StatusOK, festival, err)
+}
+
+func (h *Handler) RemoveVideoEmbed(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    id, err := festivalID(c)
+    if err != nil {
+        return err
+    }
+
+    festival, err := h.festivals.RemoveVideoEmbed(ctx, id, c.Param("item_id"))
+    return mediaResponse(c, http.StatusOK, festival, err)
+}
+
+func festivalID(c echo.Context) (pgtype.UUID, error) {
+    id, err := uuid.Parse(c.Param("id"))
+    if err != nil {
+        return pgtype.UUID{}, echo.NewHTTPError(http.StatusBadRequest, "invalid festival id")
+    }
+
+    return pgtype.UUID{Bytes: id, Valid: true}, nil
+}
+
+// mediaResponse maps the errors shared by the media endpoints, which all
+// respond with the updated festival.
+func mediaResponse(c echo.Context, status int, festival db.Festival, err error) error {
+    if errors.Is(err, service.ErrInvalidMedia) {
+        return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+    }
+    if errors.Is(err, service.ErrFestivalNotFound) {
+        return echo.NewHTTPError(http.StatusNotFound, "festival not found")
+    }
+    if errors.Is(err, service.ErrMediaNotFound) {
+        return echo.NewHTTPError(http.StatusNotFound, "media item not found")
+    }
+    if err != nil {
+        return echo.NewHTTPError(http.StatusInternalServerError, "failed to update festival media")
+    }
+
+    return festivalResponse(c, status, festival)
+}
@@ -2,11 +2,15 @@ package handler
 
 import (
     "errors"
+    "io"
+    "mime"
     "net/http"
     "strconv"
     "time"
 
+    "github.com/aidantrabs/kultur/backend/internal/calendar"
     "github.com/aidantrabs/kultur/backend/internal/db"
+    "github.com/aidantrabs/kultur/backend/internal/model"
     "github.com/aidantrabs/kultur/backend/internal/service"
     "github.com/google/uuid"
     "github.com/jackc/pgx/v5/pgtype"
@@ -16,15 +20,54 @@ import (
 func (h *Handler) ListFestivals(c echo.Context) error {
     ctx := c.Request().Context()
 
-    festivals, err := h.festivals.List(ctx, service.ListFestivalsParams{
-        Region:   c.QueryParam("region"),
-        Heritage: c.QueryParam("heritage"),
-    })
+    params := service.ListFestivalsParams{
+        Region:       c.QueryParam("region"),
+        HeritageType: c.QueryParam("heritage_type"),
+        FestivalType: c.QueryParam("festival_type"),
+        DateType:     c.QueryParam("date_type"),
+        Sort:         c.QueryParam("sort"),
+        Cursor:       c.QueryParam("cursor"),
+    }
+
+    // heritage is the older name of the filter
+    if params.HeritageType == "" {
+        params.HeritageType = c.QueryParam("heritage")
+    }
+
+    if v := c.QueryParam("month"); v != "" {
+        month, err := strconv.Atoi(v)
+        if err != nil {
+            return echo.NewHTTPError(http.StatusBadRequest, "invalid month")
+        }
+        params.Month = month
+    }
+
+    var err error
+    if params.HasUpcoming, err = queryBool(c, "has_upcoming"); err != nil {
+        return err
+    }
+    if params.From, err = queryDate(c, "from"); err != nil {
+        return err
+    }
+    if params.To, err = queryDate(c, "to"); err != nil {
+        return err
+    }
+    if params.Limit, err = queryLimit(c); err != nil {
+        return err
+    }
+
+    page, err := h.festivals.List(ctx, params)
+    if errors.Is(err, service.ErrInvalidFilter) {
+        return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+    }
+    if errors.Is(err, service.ErrInvalidCursor) {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid cursor")
+    }
     if err != nil {
         return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch festivals")
     }
 
-    return c.JSON(http.StatusOK, festivals)
+    return c.JSON(http.StatusOK, service.MapPage(page, model.NewFestivalListItem))
 }
 
 func (h *Handler) ListUpcomingFestivals(c echo.Context) error {
@@ -35,7 +78,7 @@ func (h *Handler) ListUpcomingFestivals(c echo.Context) error {
         return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch upcoming festivals")
     }
 
-    return c.JSON(http.StatusOK, festivals)
+    return c.JSON(http.StatusOK, model.Map(festivals, model.NewUpcomingCalendarEntry))
 }
 
 func (h *Handler) ListFestivalsByYear(c echo.Context) error {
@@ -56,7 +99,7 @@ func (h *Handler) ListFestivalsByYear(c echo.Context) error {
         return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch festivals")
     }
 
-    return c.JSON(http.StatusOK, festivals)
+    return c.JSON(http.StatusOK, model.Map(festivals, model.NewYearCalendarEntry))
 }
 
 func (h *Handler) GetFestival(c echo.Context) error {
@@ -70,7 +113,7 @@ func (h *Handler) GetFestival(c echo.Context) error {
         return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch festival")
     }
 
-    return c.JSON(http.StatusOK, festival)
+    return c.JSON(http.StatusOK, model.NewFestival(festival))
 }
 
 func (h *Handler) GetFestivalDates(c echo.Context) error {
@@ -89,7 +132,7 @@ func (h *Handler) GetFestivalDates(c echo.Context) error {
         return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch festival dates")
     }
 
-    return c.JSON(http.StatusOK, dates)
+    return c.JSON(http.StatusOK, model.Map(dates, model.NewFestivalDate))
 }
 
 type CreateFestivalRequest struct {
@@ -108,6 +151,33 @@ type CreateFestivalRequest struct {
     IsPublished      bool   `json:"is_published"`
 }
 
+// fields maps the request to the service's fields, storing empty optional
+// fields as NULL.
+func (r CreateFestivalRequest) fields() service.FestivalFields {
+    optional := func(s string) *string {
+        if s == "" {
+            return nil
+        }
+        return &s
+    }
+
+    return service.FestivalFields{
+        Slug:             r.Slug,
+        Name:             r.Name,
+        DateType:         r.DateType,
+        Region:           r.Region,
+        HeritageType:     r.HeritageType,
+        FestivalType:     r.FestivalType,
+        Summary:          r.Summary,
+        Story:            optional(r.Story),
+        WhatToExpect:     optional(r.WhatToExpect),
+        HowToParticipate: optional(r.HowToParticipate),
+        PracticalInfo:    optional(r.PracticalInfo),
+        CoverImageURL:    optional(r.CoverImageUrl),
+        IsPublished:      r.IsPublished,
+    }
+}
+
 func (h *Handler) CreateFestival(c echo.Context) error {
     ctx := c.Request().Context()
 
@@ -116,40 +186,42 @@ func (h *Handler) CreateFestival(c echo.Context) error {
         return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
     }
 
-    if req.Slug == "" || req.Name == "" {
-        return echo.NewHTTPError(http.StatusBadRequest, "slug and name are required")
-    }
-
-    festival, err := h.festivals.Create(ctx, db.CreateFestivalParams{
-        Slug:             req.Slug,
-        Name:             req.Name,
-        DateType:         req.DateType,
-        Region:           req.Region,
-        HeritageType:     req.HeritageType,
-        FestivalType:     req.FestivalType,
-        Summary:          req.Summary,
-        Story:            pgtype.Text{String: req.Story, Valid: req.Story != ""},
-        WhatToExpect:     pgtype.Text{String: req.WhatToExpect, Valid: req.WhatToExpect != ""},
-        HowToParticipate: pgtype.Text{String: req.HowToParticipate, Valid: req.HowToParticipate != ""},
-        PracticalInfo:    pgtype.Text{String: req.PracticalInfo, Valid: req.PracticalInfo != ""},
-        CoverImageUrl:    pgtype.Text{String: req.CoverImageUrl, Valid: req.CoverImageUrl != ""},
-        GalleryImages:    []byte("[]"),
-        VideoEmbeds:      []byte("[]"),
-        IsPublished:      pgtype.Bool{Bool: req.IsPublished, Valid: true},
-    })
+    festival, err := h.festivals.Create(ctx, req.fields())
     if err != nil {
-        return echo.NewHTTPError(http.StatusInternalServerError, "failed to create festival")
+        return festivalEditError(err, "failed to create festival")
     }
 
-    return c.JSON(http.StatusCreated, festival)
+    return festivalResponse(c, http.StatusCreated, festival)
 }
 
+func (h *Handler) GetAdminFestival(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    id, err := festivalID(c)
+    if err != nil {
+        return err
+    }
+
+    festival, err := h.festivals.GetByID(ctx, id)
+    if errors.Is(err, service.ErrFestivalNotFound) {
+        return echo.NewHTTPError(http.StatusNotFound, "festival not found")
+    }
+    if err != nil {
+        return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch festival")
+    }
+
+    return festivalResponse(c, http.StatusOK, festival)
+}
+
+// UpdateFestival replaces every editable field, so omitted fields are
+// cleared. An If-Match header is honoured but not required; PatchFestival is
+// the safer way to change a few fields.
 func (h *Handler) UpdateFestival(c echo.Context) error {
     ctx := c.Request().Context()
 
-    id, err := uuid.Parse(c.Param("id"))
+    id, err := festivalID(c)
     if err != nil {
-        return echo.NewHTTPError(http.StatusBadRequest, "invalid festival id")
+        return err
     }
 
     var req CreateFestivalRequest
@@ -157,32 +229,71 @@ func (h *Handler) UpdateFestival(c echo.Context) error {
         return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
     }
 
-    festival, err := h.festivals.Update(ctx, db.UpdateFestivalParams{
-        ID:               pgtype.UUID{Bytes: id, Valid: true},
-        Slug:             req.Slug,
-        Name:             req.Name,
-        DateType:         req.DateType,
-        Region:           req.Region,
-        HeritageType:     req.HeritageType,
-        FestivalType:     req.FestivalType,
-        Summary:          req.Summary,
-        Story:            pgtype.Text{String: req.Story, Valid: req.Story != ""},
-        WhatToExpect:     pgtype.Text{String: req.WhatToExpect, Valid: req.WhatToExpect != ""},
-        HowToParticipate: pgtype.Text{String: req.HowToParticipate, Valid: req.HowToParticipate != ""},
-        PracticalInfo:    pgtype.Text{String: req.PracticalInfo, Valid: req.PracticalInfo != ""},
-        CoverImageUrl:    pgtype.Text{String: req.CoverImageUrl, Valid: req.CoverImageUrl != ""},
-        GalleryImages:    []byte("[]"),
-        VideoEmbeds:      []byte("[]"),
-        IsPublished:      pgtype.Bool{Bool: req.IsPublished, Valid: true},
-    })
-    if errors.Is(err, service.ErrFestivalNotFound) {
-        return echo.NewHTTPError(http.StatusNotFound, "festival not found")
+    festival, err := h.festivals.Update(ctx, id, req.fields(), c.Request().Header.Get("If-Match"))
+    if err != nil {
+        return festivalEditError(err, "failed to update festival")
+    }
+
+    return festivalResponse(c, http.StatusOK, festival)
+}
+
+// maxPatchBytes is far more than any festival's fields need.
+const maxPatchBytes = 1 << 20
+
+// PatchFestival applies a JSON Merge Patch. It requires an If-Match header
+// with the ETag of the version being edited, so admins can't overwrite each
+// other's changes unknowingly.
+func (h *Handler) PatchFestival(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    id, err := festivalID(c)
+    if err != nil {
+        return err
+    }
+
+    mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+    if mediaType != "application/merge-patch+json" && mediaType != echo.MIMEApplicationJSON {
+        return echo.NewHTTPError(http.StatusUnsupportedMediaType, "use Content-Type: application/merge-patch+json")
+    }
+
+    ifMatch := c.Request().Header.Get("If-Match")
+    if ifMatch == "" {
+        return echo.NewHTTPError(http.StatusPreconditionRequired, "If-Match header is required")
     }
+
+    patch, err := io.ReadAll(io.LimitReader(c.Request().Body, maxPatchBytes+1))
+    if err != nil || len(patch) > maxPatchBytes {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+    }
+
+    festival, err := h.festivals.Patch(ctx, id, patch, ifMatch)
     if err != nil {
-        return echo.NewHTTPError(http.StatusInternalServerError, "failed to update festival")
+        return festivalEditError(err, "failed to update festival")
     }
 
-    return c.JSON(http.StatusOK, festival)
+    return festivalResponse(c, http.StatusOK, festival)
+}
+
+// festivalResponse writes a festival for admins along with its ETag, for
+// use in If-Match.
+func festivalResponse(c echo.Context, status int, festival db.Festival) error {
+    c.Response().Header().Set("ETag", service.FestivalETag(festival))
+    return c.JSON(status, model.NewAdminFestival(festival))
+}
+
+func festivalEditError(err error, message string) error {
+    switch {
+    case errors.Is(err, service.ErrInvalidFestival), errors.Is(err, service.ErrInvalidPatch):
+        return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+    case errors.Is(err, service.ErrFestivalNotFound):
+        return echo.NewHTTPError(http.StatusNotFound, "festival not found")
+    case errors.Is(err, service.ErrSlugTaken):
+        return echo.NewHTTPError(http.StatusConflict, "slug already in use")
+    case errors.Is(err, service.ErrFestivalChanged):
+        return echo.NewHTTPError(http.StatusPreconditionFailed, "festival was changed since it was fetched, fetch it again and reapply your changes")
+    default:
+        return echo.NewHTTPError(http.StatusInternalServerError, message)
+    }
 }
 
 func (h *Handler) DeleteFestival(c echo.Context) error {
@@ -200,6 +311,161 @@ func (h *Handler) DeleteFestival(c echo.Context) error {
     return c.NoContent(http.StatusNoContent)
 }
 
+type SetRecurrenceRequest struct {
+    Recurrence *calendar.Rule `json:"recurrence"`
+}
+
+func (h *Handler) SetFestivalRecurrence(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    id, err := uuid.Parse(c.Param("id"))
+    if err != nil {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid festival id")
+    }
+
+    var req SetRecurrenceRequest
+    if err := c.Bind(&req); err != nil {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+    }
+
+    festival, err := h.festivals.SetRecurrence(ctx, pgtype.UUID{Bytes: id, Valid: true}, req.Recurrence)
+    if errors.Is(err, service.ErrInvalidRecurrence) {
+        return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+    }
+    if errors.Is(err, service.ErrFestivalNotFound) {
+        return echo.NewHTTPError(http.StatusNotFound, "festival not found")
+    }
+    if err != nil {
+        return echo.NewHTTPError(http.StatusInternalServerError, "failed to update recurrence")
+    }
+
+    return festivalResponse(c, http.StatusOK, festival)
+}
+
+func (h *Handler) GetEasterDates(c echo.Context) error {
+    yearStr := c.QueryParam("year")
+    if yearStr == "" {
+        yearStr = strconv.Itoa(time.Now().Year())
+    }
+
+    year, err := strconv.Atoi(yearStr)
+    if err != nil || year < 1583 {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid year")
+    }
+
+    return c.JSON(http.StatusOK, map[string]any{
+        "year":  year,
+        "dates": h.festivals.EasterDates(year),
+    })
+}
+
+type PrefillEasterDatesRequest struct {
+    FromYear int `json:"from_year"`
+    ToYear   int `json:"to_year"`
+}
+
+func (h *Handler) PrefillEasterDates(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    var req PrefillEasterDatesRequest
+    if err := c.Bind(&req); err != nil {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+    }
+
+    written, err := h.festivals.PrefillEasterDates(ctx, req.FromYear, req.ToYear)
+    if errors.Is(err, service.ErrInvalidYearRange) {
+        return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+    }
+    if err != nil {
+        return echo.NewHTTPError(http.StatusInternalServerError, "failed to pre-fill festival dates")
+    }
+
+    return c.JSON(http.StatusOK, map[string]any{
+        "fromYear": req.FromYear,
+        "toYear":   req.ToYear,
+        "written":  written,
+    })
+}
+
+func (h *Handler) GetLunarEstimates(c echo.Context) error {
+    yearStr := c.QueryParam("year")
+    if yearStr == "" {
+        yearStr = strconv.Itoa(time.Now().Year())
+    }
+
+    year, err := strconv.Atoi(yearStr)
+    if err != nil || year < 1900 || year > 2100 {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid year")
+    }
+
+    return c.JSON(http.StatusOK, map[string]any{
+        "year":      year,
+        "estimates": h.festivals.LunarEstimates(year),
+    })
+}
+
+func (h *Handler) ListTentativeFestivalDates(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    dates, err := h.festivals.ListTentativeDates(ctx)
+    if err != nil {
+        return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch festival dates")
+    }
+
+    return c.JSON(http.StatusOK, model.Map(dates, model.NewTentativeFestivalDate))
+}
+
+type ConfirmFestivalDateRequest struct {
+    StartDate string `json:"start_date"`
+    EndDate   string `json:"end_date"`
+}
+
+func (h *Handler) ConfirmFestivalDate(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    id, err := uuid.Parse(c.Param("id"))
+    if err != nil {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid date id")
+    }
+
+    var req ConfirmFestivalDateRequest
+    if err := c.Bind(&req); err != nil {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+    }
+
+    var startDate, endDate *time.Time
+    if req.StartDate != "" {
+        sd, err := time.Parse("2006-01-02", req.StartDate)
+        if err != nil {
+            return echo.NewHTTPError(http.StatusBadRequest, "invalid start_date format (use YYYY-MM-DD)")
+        }
+
+        startDate = &sd
+    }
+
+    if req.EndDate != "" {
+        ed, err := time.Parse("2006-01-02", req.EndDate)
+        if err != nil {
+            return echo.NewHTTPError(http.StatusBadRequest, "invalid end_date format (use YYYY-MM-DD)")
+        }
+
+        endDate = &ed
+    }
+
+    date, err := h.festivals.ConfirmDate(ctx, pgtype.UUID{Bytes: id, Valid: true}, startDate, endDate)
+    if errors.Is(err, service.ErrFestivalDateNotFound) {
+        return echo.NewHTTPError(http.StatusNotFound, "festival date not found")
+    }
+    if errors.Is(err, service.ErrInvalidDateRange) {
+        return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+    }
+    if err != nil {
+        return echo.NewHTTPError(http.StatusInternalServerError, "failed to confirm festival date")
+    }
+
+    return c.JSON(http.StatusOK, model.NewFestivalDate(date))
+}
+
 type CreateFestivalDateRequest struct {
     FestivalID  string `json:"festival_id"`
     Year        int    `json:"year"`
@@ -247,7 +513,7 @@ func (h *Handler) CreateFestivalDate(c echo.Context) error {
         return echo.NewHTTPError(http.StatusInternalServerError, "failed to create festival date")
     }
 
-    return c.JSON(http.StatusCreated, date)
+    return c.JSON(http.StatusCreated, model.NewFestivalDate(date))
 }
 
 type UpdateFestivalDateRequest struct {
@@ -297,7 +563,7 @@ func (h *Handler) UpdateFestivalDate(c echo.Context) error {
         return echo.NewHTTPError(http.StatusInternalServerError, "failed to update festival date")
     }
 
-    return c.JSON(http.StatusOK, date)
+    return c.JSON(http.StatusOK, model.NewFestivalDate(date))
 }
 
 func (h *Handler) DeleteFestivalDate(c echo.Context) error {
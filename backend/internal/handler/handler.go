@@ -1,7 +1,6 @@
 package handler
 
 import (
-    "github.com/aidantrabs/kultur/backend/internal/db"
     "github.com/aidantrabs/kultur/backend/internal/email"
     "github.com/aidantrabs/kultur/backend/internal/service"
     "github.com/jackc/pgx/v5/pgxpool"
@@ -12,30 +11,34 @@ type Handler struct {
     festivals     *service.FestivalService
     memories      *service.MemoryService
     subscriptions *service.SubscriptionService
+    previews      *service.PreviewService
+    calendars     *service.CalendarService
+    search        *service.SearchService
+    media         *service.MediaService
     email         *email.Service
 }
 
-type Config struct {
-    ResendAPIKey string
-    FromEmail    string
-    BaseURL      string
+type Services struct {
+    Festivals     *service.FestivalService
+    Memories      *service.MemoryService
+    Subscriptions *service.SubscriptionService
+    Previews      *service.PreviewService
+    Calendars     *service.CalendarService
+    Search        *service.SearchService
+    Media         *service.MediaService
+    Email         *email.Service
 }
 
-func New(pool *pgxpool.Pool, cfg Config) *Handler {
-    queries := db.New(pool)
-    festivalSvc := service.NewFestivalService(queries)
-
-    emailSvc := email.NewService(email.Config{
-        APIKey:    cfg.ResendAPIKey,
-        FromEmail: cfg.FromEmail,
-        BaseURL:   cfg.BaseURL,
-    })
-
+func New(pool *pgxpool.Pool, svc Services) *Handler {
     return &Handler{
         pool:          pool,
-        festivals:     festivalSvc,
-        memories:      service.NewMemoryService(queries, festivalSvc),
-        subscriptions: service.NewSubscriptionService(queries, emailSvc),
-        email:         emailSvc,
+        festivals:     svc.Festivals,
+        memories:      svc.Memories,
+        subscriptions: svc.Subscriptions,
+        previews:      svc.Previews,
+        calendars:     svc.Calendars,
+        search:        svc.Search,
+        media:         svc.Media,
+        email:         svc.Email,
     }
 }
@@ -0,0 +1,89 @@
+package handler
+
+import (
+    "errors"
+    "net/http"
+
+    "github.com/aidantrabs/kultur/backend/internal/model"
+    "github.com/aidantrabs/kultur/backend/internal/service"
+    "github.com/google/uuid"
+    "github.com/jackc/pgx/v5/pgtype"
+    "github.com/labstack/echo/v4"
+)
+
+// multipartOverhead leaves room for the boundaries and headers around the
+// file in a multipart body.
+const multipartOverhead = 64 << 10
+
+func (h *Handler) UploadMedia(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    req := c.Request()
+    req.Body = http.MaxBytesReader(c.Response(), req.Body, h.media.MaxUploadBytes()+multipartOverhead)
+
+    header, err := c.FormFile("file")
+    if err != nil {
+        var tooLarge *http.MaxBytesError
+        if errors.As(err, &tooLarge) {
+            return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "upload too large")
+        }
+        return echo.NewHTTPError(http.StatusBadRequest, "file is required")
+    }
+
+    file, err := header.Open()
+    if err != nil {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid file")
+    }
+    defer file.Close()
+
+    asset, err := h.media.Upload(ctx, header.Filename, file)
+    if errors.Is(err, service.ErrUploadTooLarge) {
+        return echo.NewHTTPError(http.StatusRequestEntityTooLarge, err.Error())
+    }
+    if errors.Is(err, service.ErrUnsupportedMedia) {
+        return echo.NewHTTPError(http.StatusUnsupportedMediaType, "file must be a JPEG, PNG or WebP image")
+    }
+    if err != nil {
+        return echo.NewHTTPError(http.StatusInternalServerError, "failed to upload media")
+    }
+
+    return c.JSON(http.StatusCreated, model.NewMediaAsset(asset.MediaAsset, asset.Variants, h.media.URL))
+}
+
+func (h *Handler) GetMedia(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    id, err := uuid.Parse(c.Param("id"))
+    if err != nil {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid media id")
+    }
+
+    asset, err := h.media.Get(ctx, pgtype.UUID{Bytes: id, Valid: true})
+    if errors.Is(err, service.ErrMediaAssetNotFound) {
+        return echo.NewHTTPError(http.StatusNotFound, "media not found")
+    }
+    if err != nil {
+        return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch media")
+    }
+
+    return c.JSON(http.StatusOK, model.NewMediaAsset(asset.MediaAsset, asset.Variants, h.media.URL))
+}
+
+func (h *Handler) DeleteMedia(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    id, err := uuid.Parse(c.Param("id"))
+    if err != nil {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid media id")
+    }
+
+    err = h.media.Delete(ctx, pgtype.UUID{Bytes: id, Valid: true})
+    if errors.Is(err, service.ErrMediaAssetNotFound) {
+        return echo.NewHTTPError(http.StatusNotFound, "media not found")
+    }
+    if err != nil {
+        return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete media")
+    }
+
+    return c.NoContent(http.StatusNoContent)
+}
@@ -4,6 +4,8 @@ import (
     "errors"
     "net/http"
 
+    "github.com/aidantrabs/kultur/backend/internal/middleware"
+    "github.com/aidantrabs/kultur/backend/internal/model"
     "github.com/aidantrabs/kultur/backend/internal/service"
     "github.com/google/uuid"
     "github.com/jackc/pgx/v5/pgtype"
@@ -16,6 +18,9 @@ type CreateMemoryRequest struct {
     AuthorEmail  string `json:"author_email"`
     Content      string `json:"content"`
     YearOfMemory string `json:"year_of_memory"`
+    NotifyAuthor *bool  `json:"notify_author"`
+    // Website is a honeypot: the form hides it, so only bots fill it in
+    Website string `json:"website"`
 }
 
 func (h *Handler) ListMemoriesByFestival(c echo.Context) error {
@@ -29,7 +34,7 @@ func (h *Handler) ListMemoriesByFestival(c echo.Context) error {
         return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch memories")
     }
 
-    return c.JSON(http.StatusOK, memories)
+    return c.JSON(http.StatusOK, model.Map(memories, model.NewMemory))
 }
 
 func (h *Handler) CreateMemory(c echo.Context) error {
@@ -53,33 +58,83 @@ func (h *Handler) CreateMemory(c echo.Context) error {
         return echo.NewHTTPError(http.StatusBadRequest, "invalid festival_id")
     }
 
+    // authors hear back about their memory unless they opt out
+    notifyAuthor := req.NotifyAuthor == nil || *req.NotifyAuthor
+
     memory, err := h.memories.Create(ctx, service.CreateMemoryParams{
         FestivalID:   pgtype.UUID{Bytes: festivalUUID, Valid: true},
         AuthorName:   req.AuthorName,
         AuthorEmail:  req.AuthorEmail,
         Content:      req.Content,
         YearOfMemory: req.YearOfMemory,
+        NotifyAuthor: notifyAuthor,
+        Honeypot:     req.Website,
     })
+    if errors.Is(err, service.ErrInvalidMemoryYear) {
+        return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+    }
+    if errors.Is(err, service.ErrInvalidEmail) {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid author_email")
+    }
+    if errors.Is(err, service.ErrFestivalNotFound) {
+        return echo.NewHTTPError(http.StatusNotFound, "festival not found")
+    }
+    if errors.Is(err, service.ErrFestivalNotPublished) {
+        return echo.NewHTTPError(http.StatusUnprocessableEntity, "festival is not accepting memories")
+    }
     if err != nil {
         return echo.NewHTTPError(http.StatusInternalServerError, "failed to create memory")
     }
 
-    return c.JSON(http.StatusCreated, memory)
+    return c.JSON(http.StatusCreated, model.NewMemory(memory))
 }
 
 func (h *Handler) ListAllMemories(c echo.Context) error {
     ctx := c.Request().Context()
 
-    memories, err := h.memories.ListAll(ctx)
+    params := service.ListMemoriesParams{
+        Status: c.QueryParam("status"),
+        Cursor: c.QueryParam("cursor"),
+    }
+
+    if v := c.QueryParam("festival_id"); v != "" {
+        festivalUUID, err := uuid.Parse(v)
+        if err != nil {
+            return echo.NewHTTPError(http.StatusBadRequest, "invalid festival_id")
+        }
+        params.FestivalID = pgtype.UUID{Bytes: festivalUUID, Valid: true}
+    }
+
+    var err error
+    if params.From, err = queryDate(c, "from"); err != nil {
+        return err
+    }
+    if params.To, err = queryDate(c, "to"); err != nil {
+        return err
+    }
+    if params.Limit, err = queryLimit(c); err != nil {
+        return err
+    }
+
+    page, err := h.memories.List(ctx, params)
+    if errors.Is(err, service.ErrInvalidFilter) {
+        return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+    }
+    if errors.Is(err, service.ErrInvalidCursor) {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid cursor")
+    }
     if err != nil {
         return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch memories")
     }
 
-    return c.JSON(http.StatusOK, memories)
+    return c.JSON(http.StatusOK, service.MapPage(page, model.NewAdminMemory))
 }
 
 type UpdateMemoryStatusRequest struct {
-    Status string `json:"status"`
+    Status  string `json:"status"`
+    Reason  string `json:"reason"`
+    Notes   string `json:"notes"`
+    Message string `json:"message"`
 }
 
 func (h *Handler) UpdateMemoryStatus(c echo.Context) error {
@@ -99,22 +154,110 @@ func (h *Handler) UpdateMemoryStatus(c echo.Context) error {
         return echo.NewHTTPError(http.StatusBadRequest, "status is required")
     }
 
-    if req.Status != "approved" && req.Status != "rejected" && req.Status != "pending" {
-        return echo.NewHTTPError(http.StatusBadRequest, "status must be approved, rejected, or pending")
+    _, err = h.memories.Review(ctx, pgtype.UUID{Bytes: id, Valid: true}, service.ReviewParams{
+        Status:   req.Status,
+        Reviewer: middleware.AdminName(c),
+        Reason:   req.Reason,
+        Notes:    req.Notes,
+        Message:  req.Message,
+    })
+    if errors.Is(err, service.ErrInvalidReview) {
+        return echo.NewHTTPError(http.StatusBadRequest, err.Error())
     }
-
-    err = h.memories.UpdateStatus(ctx, pgtype.UUID{Bytes: id, Valid: true}, req.Status)
     if errors.Is(err, service.ErrMemoryNotFound) {
         return echo.NewHTTPError(http.StatusNotFound, "memory not found")
     }
-
-		if err != nil {
+    if err != nil {
         return echo.NewHTTPError(http.StatusInternalServerError, "failed to update memory status")
     }
 
     return c.JSON(http.StatusOK, map[string]string{"status": "updated"})
 }
 
+func (h *Handler) MemoryQueue(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    limit, err := queryLimit(c)
+    if err != nil {
+        return err
+    }
+
+    page, err := h.memories.Queue(ctx, c.QueryParam("cursor"), limit)
+    if errors.Is(err, service.ErrInvalidCursor) {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid cursor")
+    }
+    if err != nil {
+        return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch moderation queue")
+    }
+
+    return c.JSON(http.StatusOK, service.MapPage(page, model.NewAdminMemory))
+}
+
+type BulkReviewMemoriesRequest struct {
+    IDs     []string `json:"ids"`
+    Status  string   `json:"status"`
+    Reason  string   `json:"reason"`
+    Notes   string   `json:"notes"`
+    Message string   `json:"message"`
+}
+
+func (h *Handler) BulkReviewMemories(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    var req BulkReviewMemoriesRequest
+    if err := c.Bind(&req); err != nil {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+    }
+
+    if req.Status != service.MemoryStatusApproved && req.Status != service.MemoryStatusRejected {
+        return echo.NewHTTPError(http.StatusBadRequest, "status must be approved or rejected")
+    }
+
+    ids := make([]pgtype.UUID, 0, len(req.IDs))
+    for _, raw := range req.IDs {
+        id, err := uuid.Parse(raw)
+        if err != nil {
+            return echo.NewHTTPError(http.StatusBadRequest, "invalid memory id "+raw)
+        }
+        ids = append(ids, pgtype.UUID{Bytes: id, Valid: true})
+    }
+
+    result, err := h.memories.BulkReview(ctx, ids, service.ReviewParams{
+        Status:   req.Status,
+        Reviewer: middleware.AdminName(c),
+        Reason:   req.Reason,
+        Notes:    req.Notes,
+        Message:  req.Message,
+    })
+    if errors.Is(err, service.ErrInvalidReview) {
+        return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+    }
+    if err != nil {
+        return echo.NewHTTPError(http.StatusInternalServerError, "failed to review memories")
+    }
+
+    return c.JSON(http.StatusOK, model.NewBulkReviewResult(result.Reviewed, result.NotFound))
+}
+
+func (h *Handler) ListMemoryReviews(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    id, err := uuid.Parse(c.Param("id"))
+    if err != nil {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid memory id")
+    }
+
+    reviews, err := h.memories.History(ctx, pgtype.UUID{Bytes: id, Valid: true})
+    if errors.Is(err, service.ErrMemoryNotFound) {
+        return echo.NewHTTPError(http.StatusNotFound, "memory not found")
+    }
+    if err != nil {
+        return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch memory reviews")
+    }
+
+    return c.JSON(http.StatusOK, model.Map(reviews, model.NewMemoryReview))
+}
+
 func (h *Handler) DeleteMemory(c echo.Context) error {
     ctx := c.Request().Context()
 
@@ -129,3 +272,19 @@ func (h *Handler) DeleteMemory(c echo.Context) error {
 
     return c.NoContent(http.StatusNoContent)
 }
+
+func (h *Handler) OptOutMemoryAuthor(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    err := h.memories.OptOutAuthor(ctx, c.Param("token"))
+    if errors.Is(err, service.ErrInvalidToken) {
+        return echo.NewHTTPError(http.StatusNotFound, "invalid unsubscribe token")
+    }
+    if err != nil {
+        return echo.NewHTTPError(http.StatusInternalServerError, "failed to unsubscribe")
+    }
+
+    return c.JSON(http.StatusOK, map[string]string{
+        "message": "you will no longer receive emails about your memories",
+    })
+}
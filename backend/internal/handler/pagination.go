@@ -0,0 +1,57 @@
+package handler
+
+import (
+    "fmt"
+    "net/http"
+    "strconv"
+    "time"
+
+    "github.com/labstack/echo/v4"
+)
+
+// Helpers for the query params shared by the paginated list endpoints. Each
+// returns nil when the param is absent and a 400 when it is malformed.
+
+func queryDate(c echo.Context, name string) (*time.Time, error) {
+    v := c.QueryParam(name)
+    if v == "" {
+        return nil, nil
+    }
+
+    t, err := time.Parse("2006-01-02", v)
+    if err != nil {
+        return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid %s format (use YYYY-MM-DD)", name))
+    }
+
+    return &t, nil
+}
+
+func queryBool(c echo.Context, name string) (*bool, error) {
+    v := c.QueryParam(name)
+    if v == "" {
+        return nil, nil
+    }
+
+    b, err := strconv.ParseBool(v)
+    if err != nil {
+        return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid %s", name))
+    }
+
+    return &b, nil
+}
+
+// queryLimit returns 0 when no limit was given, leaving the default to the
+// service.
+func queryLimit(c echo.Context) (int, error) {
+    v := c.QueryParam("limit")
+    if v == "" {
+        return 0, nil
+    }
+
+    limit, err := strconv.Atoi(v)
+    if err != nil || limit < 1 {
+        return 0, echo.NewHTTPError(http.StatusBadRequest, "invalid limit")
+    }
+
+    return limit, nil
+}
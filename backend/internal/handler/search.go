@@ -0,0 +1,24 @@
+package handler
+
+import (
+    "errors"
+    "net/http"
+
+    "github.com/aidantrabs/kultur/backend/internal/model"
+    "github.com/aidantrabs/kultur/backend/internal/service"
+    "github.com/labstack/echo/v4"
+)
+
+func (h *Handler) Search(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    results, err := h.search.Search(ctx, c.QueryParam("q"))
+    if errors.Is(err, service.ErrInvalidQuery) {
+        return echo.NewHTTPError(http.StatusBadRequest, "q is required")
+    }
+    if err != nil {
+        return echo.NewHTTPError(http.StatusInternalServerError, "failed to search")
+    }
+
+    return c.JSON(http.StatusOK, model.NewSearchResults(results.Query, results.Festivals, results.Memories))
+}
@@ -4,16 +4,17 @@ import (
     "errors"
     "net/http"
 
+    "github.com/aidantrabs/kultur/backend/internal/model"
     "github.com/aidantrabs/kultur/backend/internal/service"
     "github.com/google/uuid"
     "github.com/jackc/pgx/v5/pgtype"
     "github.com/labstack/echo/v4"
 )
 
-
 type SubscribeRequest struct {
-    Email        string `json:"email"`
-    DigestWeekly bool   `json:"digest_weekly"`
+    Email             string                     `json:"email"`
+    DigestWeekly      bool                       `json:"digest_weekly"`
+    FestivalReminders []service.FestivalReminder `json:"festival_reminders"`
 }
 
 func (h *Handler) Subscribe(c echo.Context) error {
@@ -28,24 +29,25 @@ func (h *Handler) Subscribe(c echo.Context) error {
         return echo.NewHTTPError(http.StatusBadRequest, "email is required")
     }
 
-    sub, err := h.subscriptions.Create(ctx, service.CreateSubscriptionParams{
-        Email:        req.Email,
-        DigestWeekly: req.DigestWeekly,
+    _, err := h.subscriptions.Create(ctx, service.CreateSubscriptionParams{
+        Email:             req.Email,
+        DigestWeekly:      req.DigestWeekly,
+        FestivalReminders: req.FestivalReminders,
     })
-    if errors.Is(err, service.ErrEmailAlreadyExists) {
-        // Return success anyway - don't reveal if email exists for privacy
-        return c.JSON(http.StatusOK, map[string]any{
-            "message": "if this email is not already subscribed, you will receive a confirmation email",
-            "id":      sub.ID,
-        })
+    if errors.Is(err, service.ErrInvalidReminders) {
+        return echo.NewHTTPError(http.StatusBadRequest, err.Error())
     }
-    if err != nil {
+    if errors.Is(err, service.ErrInvalidEmail) {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid email")
+    }
+    if err != nil && !errors.Is(err, service.ErrEmailAlreadyExists) {
         return echo.NewHTTPError(http.StatusInternalServerError, "failed to create subscription")
     }
 
-    return c.JSON(http.StatusCreated, map[string]any{
-        "message": "subscription created, please check your email to confirm",
-        "id":      sub.ID,
+    // the same response whether or not the address is already subscribed, so
+    // it can't be used to find out who is
+    return c.JSON(http.StatusAccepted, map[string]string{
+        "message": "check your email to confirm your subscription",
     })
 }
 
@@ -56,6 +58,9 @@ func (h *Handler) ConfirmSubscription(c echo.Context) error {
     if errors.Is(err, service.ErrInvalidToken) {
         return echo.NewHTTPError(http.StatusNotFound, "invalid confirmation token")
     }
+    if errors.Is(err, service.ErrTokenExpired) {
+        return echo.NewHTTPError(http.StatusGone, "confirmation link has expired, please subscribe again")
+    }
     if err != nil {
         return echo.NewHTTPError(http.StatusInternalServerError, "failed to confirm subscription")
     }
@@ -81,15 +86,86 @@ func (h *Handler) Unsubscribe(c echo.Context) error {
     })
 }
 
+type UpdateRemindersRequest struct {
+    FestivalReminders []service.FestivalReminder `json:"festival_reminders"`
+}
+
+func (h *Handler) GetReminders(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    reminders, err := h.subscriptions.GetReminders(ctx, c.Param("token"))
+    if errors.Is(err, service.ErrInvalidToken) {
+        return echo.NewHTTPError(http.StatusNotFound, "invalid subscription token")
+    }
+    if err != nil {
+        return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch reminders")
+    }
+
+    return c.JSON(http.StatusOK, map[string]any{
+        "festivalReminders": reminders,
+    })
+}
+
+func (h *Handler) UpdateReminders(c echo.Context) error {
+    ctx := c.Request().Context()
+
+    var req UpdateRemindersRequest
+    if err := c.Bind(&req); err != nil {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+    }
+
+    reminders, err := h.subscriptions.UpdateReminders(ctx, c.Param("token"), req.FestivalReminders)
+    if errors.Is(err, service.ErrInvalidToken) {
+        return echo.NewHTTPError(http.StatusNotFound, "invalid subscription token")
+    }
+    if errors.Is(err, service.ErrInvalidReminders) {
+        return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+    }
+    if err != nil {
+        return echo.NewHTTPError(http.StatusInternalServerError, "failed to update reminders")
+    }
+
+    return c.JSON(http.StatusOK, map[string]any{
+        "festivalReminders": reminders,
+    })
+}
+
 func (h *Handler) ListAllSubscriptions(c echo.Context) error {
     ctx := c.Request().Context()
 
-    subs, err := h.subscriptions.ListAll(ctx)
+    params := service.ListSubscriptionsParams{
+        Cursor: c.QueryParam("cursor"),
+    }
+
+    var err error
+    if params.Confirmed, err = queryBool(c, "confirmed"); err != nil {
+        return err
+    }
+    if params.DigestWeekly, err = queryBool(c, "digest_weekly"); err != nil {
+        return err
+    }
+    if params.From, err = queryDate(c, "from"); err != nil {
+        return err
+    }
+    if params.To, err = queryDate(c, "to"); err != nil {
+        return err
+    }
+    if params.Limit, err = queryLimit(c); err != nil {
+        return err
+    }
+
+    page, err := h.subscriptions.List(ctx, params)
+    if errors.Is(err, service.ErrInvalidFilter) {
+        return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+    }
+    if errors.Is(err, service.ErrInvalidCursor) {
+        return echo.NewHTTPError(http.StatusBadRequest, "invalid cursor")
+    }
     if err != nil {
         return echo.NewHTTPError(http.StatusInternalServerError, "failed to fetch subscriptions")
     }
 
-    return c.JSON(http.StatusOK, subs)
+    return c.JSON(http.StatusOK, service.MapPage(page, model.NewAdminSubscription))
 }
 
 func (h *Handler) DeleteSubscription(c echo.Context) error {
@@ -0,0 +1,183 @@
+// Package ical writes RFC 5545 iCalendar feeds of all-day events.
+package ical
+
+import (
+    "bytes"
+    "fmt"
+    "strings"
+    "time"
+    "unicode/utf8"
+)
+
+// TimeZone is the zone every feed is published in. Trinidad and Tobago
+// stays on AST all year.
+const TimeZone = "America/Port_of_Spain"
+
+const (
+    StatusConfirmed = "CONFIRMED"
+    StatusTentative = "TENTATIVE"
+)
+
+type Calendar struct {
+    Name        string
+    Description string
+    Events      []Event
+}
+
+// Event is an all-day event. End is the last day of the event, inclusive.
+type Event struct {
+    UID         string
+    Summary     string
+    Description string
+    Location    string
+    URL         string
+    Start       time.Time
+    End         time.Time
+    Status      string
+    Alarms      []Alarm
+}
+
+// Alarm is a display reminder DaysBefore days ahead of an event, at Hour
+// local time. DaysBefore 0 reminds on the day itself.
+type Alarm struct {
+    DaysBefore  int
+    Hour        int
+    Description string
+}
+
+// Encode renders the calendar, using stamp as every event's DTSTAMP.
+func (c Calendar) Encode(stamp time.Time) []byte {
+    w := &writer{}
+
+    w.line("BEGIN:VCALENDAR")
+    w.line("VERSION:2.0")
+    w.line("PRODID:-//KULTUR//Festival Calendar//EN")
+    w.line("CALSCALE:GREGORIAN")
+    w.line("METHOD:PUBLISH")
+    w.prop("X-WR-CALNAME", c.Name)
+    if c.Description != "" {
+        w.prop("X-WR-CALDESC", c.Description)
+    }
+    w.line("X-WR-TIMEZONE:" + TimeZone)
+    w.line("REFRESH-INTERVAL;VALUE=DURATION:PT12H")
+    w.line("X-PUBLISHED-TTL:PT12H")
+
+    w.line("BEGIN:VTIMEZONE")
+    w.line("TZID:" + TimeZone)
+    w.line("BEGIN:STANDARD")
+    w.line("DTSTART:19700101T000000")
+    w.line("TZOFFSETFROM:-0400")
+    w.line("TZOFFSETTO:-0400")
+    w.line("TZNAME:AST")
+    w.line("END:STANDARD")
+    w.line("END:VTIMEZONE")
+
+    for _, e := range c.Events {
+        e.encode(w, stamp)
+    }
+
+    w.line("END:VCALENDAR")
+
+    return w.buf.Bytes()
+}
+
+func (e Event) encode(w *writer, stamp time.Time) {
+    end := e.End
+    if end.Before(e.Start) {
+        end = e.Start
+    }
+
+    w.line("BEGIN:VEVENT")
+    w.line("UID:" + e.UID)
+    w.line("DTSTAMP:" + stamp.UTC().Format("20060102T150405Z"))
+    w.line("DTSTART;VALUE=DATE:" + e.Start.Format("20060102"))
+    // DTEND is exclusive for all-day events
+    w.line("DTEND;VALUE=DATE:" + end.AddDate(0, 0, 1).Format("20060102"))
+    w.prop("SUMMARY", e.Summary)
+    if e.Description != "" {
+        w.prop("DESCRIPTION", e.Description)
+    }
+    if e.Location != "" {
+        w.prop("LOCATION", e.Location)
+    }
+    if e.URL != "" {
+        w.line("URL:" + e.URL)
+    }
+    if e.Status != "" {
+        w.line("STATUS:" + e.Status)
+    }
+    w.line("TRANSP:TRANSPARENT")
+
+    for _, a := range e.Alarms {
+        w.line("BEGIN:VALARM")
+        w.line("ACTION:DISPLAY")
+        w.prop("DESCRIPTION", a.Description)
+        w.line("TRIGGER:" + a.trigger())
+        w.line("END:VALARM")
+    }
+
+    w.line("END:VEVENT")
+}
+
+// trigger is relative to the start of the event, which is midnight for
+// all-day events.
+func (a Alarm) trigger() string {
+    offset := time.Duration(a.Hour)*time.Hour - time.Duration(a.DaysBefore)*24*time.Hour
+    if offset >= 0 {
+        return "PT" + durationString(offset)
+    }
+
+    return "-PT" + durationString(-offset)
+}
+
+func durationString(d time.Duration) string {
+    h := int(d / time.Hour)
+    m := int(d % time.Hour / time.Minute)
+    if m == 0 {
+        return fmt.Sprintf("%dH", h)
+    }
+
+    return fmt.Sprintf("%dH%dM", h, m)
+}
+
+type writer struct {
+    buf bytes.Buffer
+}
+
+func (w *writer) prop(name, value string) {
+    w.line(name + ":" + escape(value))
+}
+
+// line writes a content line, folded at 75 octets without splitting UTF-8
+// sequences.
+func (w *writer) line(s string) {
+    // continuation lines start with a space, which counts toward the limit
+    limit := 75
+
+    for len(s) > limit {
+        cut := limit
+        for cut > 0 && !utf8.RuneStart(s[cut]) {
+            cut--
+        }
+
+        w.buf.WriteString(s[:cut])
+        w.buf.WriteString("\r\n ")
+        s = s[cut:]
+        limit = 74
+    }
+
+    w.buf.WriteString(s)
+    w.buf.WriteString("\r\n")
+}
+
+var escaper = strings.NewReplacer(
+    `\`, `\\`,
+    ";", `\;`,
+    ",", `\,`,
+    "\r\n", `\n`,
+    "\n", `\n`,
+)
+
+func escape(s string) string {
+    return escaper.Replace(s)
+}
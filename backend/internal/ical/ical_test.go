@@ -0,0 +1,98 @@
+package ical
+
+import (
+    "bytes"
+    "strings"
+    "testing"
+    "time"
+    "unicode/utf8"
+)
+
+func TestLineFolding(t *testing.T) {
+    tests := []struct {
+        name string
+        line string
+    }{
+        {"short", "SUMMARY:Divali"},
+        {"exactly 75", "SUMMARY:" + strings.Repeat("a", 67)},
+        {"ascii", "DESCRIPTION:" + strings.Repeat("Carnival Monday and Tuesday ", 12)},
+        {"multibyte", "DESCRIPTION:" + strings.Repeat("Fête à Port of Spain ", 10)},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            w := &writer{}
+            w.line(tt.line)
+
+            out := w.buf.String()
+            if !strings.HasSuffix(out, "\r\n") {
+                t.Fatalf("line doesn't end in CRLF: %q", out)
+            }
+
+            physical := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+            for i, l := range physical {
+                if len(l) > 75 {
+                    t.Errorf("line %d is %d octets, want at most 75", i, len(l))
+                }
+                if i > 0 && !strings.HasPrefix(l, " ") {
+                    t.Errorf("continuation line %d doesn't start with a space", i)
+                }
+                if !utf8.ValidString(l) {
+                    t.Errorf("line %d splits a UTF-8 sequence", i)
+                }
+            }
+
+            if len(tt.line) <= 75 && len(physical) != 1 {
+                t.Errorf("folded a %d octet line", len(tt.line))
+            }
+
+            if unfolded := strings.ReplaceAll(strings.TrimSuffix(out, "\r\n"), "\r\n ", ""); unfolded != tt.line {
+                t.Errorf("unfolded = %q, want %q", unfolded, tt.line)
+            }
+        })
+    }
+}
+
+func TestEscape(t *testing.T) {
+    got := escape("Fireworks; music, food\nand a \\ backslash")
+    want := `Fireworks\; music\, food\nand a \\ backslash`
+    if got != want {
+        t.Errorf("escape = %q, want %q", got, want)
+    }
+}
+
+func TestAlarmTrigger(t *testing.T) {
+    tests := []struct {
+        alarm Alarm
+        want  string
+    }{
+        {Alarm{DaysBefore: 0, Hour: 9}, "PT9H"},
+        {Alarm{DaysBefore: 1, Hour: 9}, "-PT15H"},
+        {Alarm{DaysBefore: 7, Hour: 9}, "-PT159H"},
+    }
+
+    for _, tt := range tests {
+        if got := tt.alarm.trigger(); got != tt.want {
+            t.Errorf("trigger(%+v) = %s, want %s", tt.alarm, got, tt.want)
+        }
+    }
+}
+
+func TestEventEndIsExclusive(t *testing.T) {
+    cal := Calendar{
+        Name: "Festivals",
+        Events: []Event{{
+            UID:     "carnival-2025",
+            Summary: "Carnival",
+            Start:   time.Date(2025, time.March, 3, 0, 0, 0, 0, time.UTC),
+            End:     time.Date(2025, time.March, 4, 0, 0, 0, 0, time.UTC),
+        }},
+    }
+
+    out := cal.Encode(time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC))
+    for _, want := range []string{"DTSTART;VALUE=DATE:20250303\r\n", "DTEND;VALUE=DATE:20250305\r\n", "DTSTAMP:20250101T120000Z\r\n"} {
+        if !bytes.Contains(out, []byte(want)) {
+            t.Errorf("feed is missing %q", want)
+        }
+    }
+}
@@ -0,0 +1,118 @@
+// Package imaging turns uploaded images into resized WebP variants. Decoding
+// and re-encoding drops all metadata, EXIF included; the EXIF orientation is
+// applied to the pixels first so photos keep their rotation.
+package imaging
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "image"
+    _ "image/jpeg"
+    _ "image/png"
+    "net/http"
+    "slices"
+
+    "github.com/HugoSmits86/nativewebp"
+    "golang.org/x/image/draw"
+    _ "golang.org/x/image/webp"
+)
+
+var (
+    ErrUnsupportedType = errors.New("unsupported image type")
+    ErrInvalidImage    = errors.New("invalid image")
+)
+
+// ContentTypes are the upload types accepted, detected from the file's
+// content rather than its name or headers.
+var ContentTypes = []string{"image/jpeg", "image/png", "image/webp"}
+
+// DefaultWidths are the widths of the variants made for responsive images.
+var DefaultWidths = []int{400, 800, 1600}
+
+// MaxPixels caps the decoded size, so a small file can't expand into an
+// image that exhausts memory.
+const MaxPixels = 40_000_000
+
+const VariantContentType = "image/webp"
+
+// Source describes the uploaded image, after orientation is applied.
+type Source struct {
+    ContentType string
+    Width       int
+    Height      int
+}
+
+type Variant struct {
+    Width  int
+    Height int
+    Data   []byte
+}
+
+// Process decodes data and encodes a WebP variant for each of widths that is
+// narrower than the image. Images narrower than the widest width also get a
+// variant at their own width, so every upload has at least one variant and
+// none are upscaled. Variants are returned narrowest first.
+func Process(data []byte, widths []int) (Source, []Variant, error) {
+    contentType := http.DetectContentType(data)
+    if !slices.Contains(ContentTypes, contentType) {
+        return Source{}, nil, fmt.Errorf("%w: %s", ErrUnsupportedType, contentType)
+    }
+
+    cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+    if err != nil {
+        return Source{}, nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+    }
+    if cfg.Width < 1 || cfg.Height < 1 || cfg.Width*cfg.Height > MaxPixels {
+        return Source{}, nil, fmt.Errorf("%w: %dx%d is too large", ErrInvalidImage, cfg.Width, cfg.Height)
+    }
+
+    img, _, err := image.Decode(bytes.NewReader(data))
+    if err != nil {
+        return Source{}, nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+    }
+
+    if contentType == "image/jpeg" {
+        img = orient(img, jpegOrientation(data))
+    }
+
+    bounds := img.Bounds()
+    source := Source{ContentType: contentType, Width: bounds.Dx(), Height: bounds.Dy()}
+
+    targets := variantWidths(source.Width, widths)
+    variants := make([]Variant, 0, len(targets))
+    for _, w := range targets {
+        h := max(1, source.Height*w/source.Width)
+
+        resized := img
+        if w != source.Width {
+            dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+            draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+            resized = dst
+        }
+
+        var buf bytes.Buffer
+        if err := nativewebp.Encode(&buf, resized, nil); err != nil {
+            return Source{}, nil, fmt.Errorf("failed to encode %dw variant: %w", w, err)
+        }
+
+        variants = append(variants, Variant{Width: w, Height: h, Data: buf.Bytes()})
+    }
+
+    return source, variants, nil
+}
+
+func variantWidths(width int, widths []int) []int {
+    var targets []int
+    for _, w := range slices.Sorted(slices.Values(widths)) {
+        if w > 0 && w < width && !slices.Contains(targets, w) {
+            targets = append(targets, w)
+        }
+    }
+
+    if len(widths) > 0 && width <= slices.Max(widths) {
+        targets = append(targets, width)
+    }
+
+    return targets
+}
@@ -0,0 +1,251 @@
+package imaging
+
+import (
+    "bytes"
+    "encoding/binary"
+    "errors"
+    "fmt"
+    "hash/crc32"
+    "image"
+    "image/color"
+    "image/jpeg"
+    "image/png"
+    "slices"
+    "strings"
+    "testing"
+)
+
+// exifJPEG encodes img as a JPEG with an APP1 EXIF segment holding the given
+// orientation. A tag before the orientation checks the IFD is walked.
+func exifJPEG(t *testing.T, img image.Image, order binary.ByteOrder, orientation uint16) []byte {
+    t.Helper()
+
+    var buf bytes.Buffer
+    if err := jpeg.Encode(&buf, img, nil); err != nil {
+        t.Fatal(err)
+    }
+    data := buf.Bytes()
+
+    tiff := make([]byte, 8+2+2*12+4)
+    if order == binary.LittleEndian {
+        copy(tiff, "II")
+    } else {
+        copy(tiff, "MM")
+    }
+    order.PutUint16(tiff[2:], 42)
+    order.PutUint32(tiff[4:], 8)
+    order.PutUint16(tiff[8:], 2)
+
+    // ImageWidth, then Orientation, both SHORT
+    entries := [][2]uint16{{0x0100, uint16(img.Bounds().Dx())}, {orientationTag, orientation}}
+    for i, e := range entries {
+        entry := tiff[10+i*12:]
+        order.PutUint16(entry[0:], e[0])
+        order.PutUint16(entry[2:], 3)
+        order.PutUint32(entry[4:], 1)
+        order.PutUint16(entry[8:], e[1])
+    }
+
+    segment := append([]byte("Exif\x00\x00"), tiff...)
+    app1 := []byte{0xFF, 0xE1, 0, 0}
+    binary.BigEndian.PutUint16(app1[2:], uint16(2+len(segment)))
+    app1 = append(app1, segment...)
+
+    out := append([]byte{}, data[:2]...)
+    out = append(out, app1...)
+
+    return append(out, data[2:]...)
+}
+
+func solid(w, h int) image.Image {
+    img := image.NewNRGBA(image.Rect(0, 0, w, h))
+    for y := 0; y < h; y++ {
+        for x := 0; x < w; x++ {
+            img.Set(x, y, color.NRGBA{R: 200, G: 100, B: 50, A: 255})
+        }
+    }
+
+    return img
+}
+
+func TestJPEGOrientation(t *testing.T) {
+    img := solid(8, 8)
+
+    var plain bytes.Buffer
+    if err := jpeg.Encode(&plain, img, nil); err != nil {
+        t.Fatal(err)
+    }
+
+    var pngData bytes.Buffer
+    if err := png.Encode(&pngData, img); err != nil {
+        t.Fatal(err)
+    }
+
+    withSix := exifJPEG(t, img, binary.LittleEndian, 6)
+
+    tests := []struct {
+        name string
+        data []byte
+        want int
+    }{
+        {"no exif", plain.Bytes(), 1},
+        {"not a jpeg", pngData.Bytes(), 1},
+        {"empty", nil, 1},
+        {"little endian", withSix, 6},
+        {"big endian", exifJPEG(t, img, binary.BigEndian, 8), 8},
+        {"out of range", exifJPEG(t, img, binary.LittleEndian, 9), 1},
+        {"zero", exifJPEG(t, img, binary.BigEndian, 0), 1},
+        {"truncated segment", withSix[:30], 1},
+    }
+
+    for o := uint16(1); o <= 8; o++ {
+        tests = append(tests, struct {
+            name string
+            data []byte
+            want int
+        }{fmt.Sprintf("orientation %d", o), exifJPEG(t, img, binary.LittleEndian, o), int(o)})
+    }
+
+    for _, tt := range tests {
+        if got := jpegOrientation(tt.data); got != tt.want {
+            t.Errorf("%s: jpegOrientation = %d, want %d", tt.name, got, tt.want)
+        }
+    }
+}
+
+func TestOrient(t *testing.T) {
+    // 3x2 with every pixel distinct
+    src := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+    for y := 0; y < 2; y++ {
+        for x := 0; x < 3; x++ {
+            src.Set(x, y, color.NRGBA{R: uint8(10*y + x + 1), A: 255})
+        }
+    }
+    topLeft, topRight := src.NRGBAAt(0, 0), src.NRGBAAt(2, 0)
+
+    tests := []struct {
+        orientation int
+        w, h        int
+        topLeftAt   image.Point
+        topRightAt  image.Point
+    }{
+        {1, 3, 2, image.Pt(0, 0), image.Pt(2, 0)},
+        {2, 3, 2, image.Pt(2, 0), image.Pt(0, 0)},
+        {3, 3, 2, image.Pt(2, 1), image.Pt(0, 1)},
+        {4, 3, 2, image.Pt(0, 1), image.Pt(2, 1)},
+        {5, 2, 3, image.Pt(0, 0), image.Pt(0, 2)},
+        {6, 2, 3, image.Pt(1, 0), image.Pt(1, 2)},
+        {7, 2, 3, image.Pt(1, 2), image.Pt(1, 0)},
+        {8, 2, 3, image.Pt(0, 2), image.Pt(0, 0)},
+        {9, 3, 2, image.Pt(0, 0), image.Pt(2, 0)},
+    }
+
+    for _, tt := range tests {
+        got := orient(src, tt.orientation)
+
+        if b := got.Bounds(); b.Dx() != tt.w || b.Dy() != tt.h {
+            t.Errorf("orientation %d: got %dx%d, want %dx%d", tt.orientation, b.Dx(), b.Dy(), tt.w, tt.h)
+            continue
+        }
+
+        at := func(p image.Point) color.NRGBA {
+            return color.NRGBAModel.Convert(got.At(p.X, p.Y)).(color.NRGBA)
+        }
+        if c := at(tt.topLeftAt); c != topLeft {
+            t.Errorf("orientation %d: top left pixel not at %v", tt.orientation, tt.topLeftAt)
+        }
+        if c := at(tt.topRightAt); c != topRight {
+            t.Errorf("orientation %d: top right pixel not at %v", tt.orientation, tt.topRightAt)
+        }
+    }
+}
+
+func TestProcessAppliesOrientation(t *testing.T) {
+    data := exifJPEG(t, solid(40, 20), binary.LittleEndian, 6)
+
+    source, variants, err := Process(data, []int{400})
+    if err != nil {
+        t.Fatalf("Process: %v", err)
+    }
+
+    if source.Width != 20 || source.Height != 40 {
+        t.Errorf("source is %dx%d, want 20x40", source.Width, source.Height)
+    }
+    if len(variants) != 1 || variants[0].Width != 20 || variants[0].Height != 40 {
+        t.Errorf("variants = %+v, want one 20x40 variant", variants)
+    }
+}
+
+func TestVariantWidths(t *testing.T) {
+    tests := []struct {
+        name   string
+        width  int
+        widths []int
+        want   []int
+    }{
+        {"wider than every width", 2000, []int{400, 800, 1600}, []int{400, 800, 1600}},
+        {"as wide as the widest", 1600, []int{400, 800, 1600}, []int{400, 800, 1600}},
+        {"between widths", 1000, []int{400, 800, 1600}, []int{400, 800, 1000}},
+        {"at a smaller width", 800, []int{400, 800, 1600}, []int{400, 800}},
+        {"narrower than every width", 300, []int{400, 800, 1600}, []int{300}},
+        {"unsorted with duplicates", 2000, []int{800, 400, 400}, []int{400, 800}},
+        {"non-positive widths", 1000, []int{0, -400, 800}, []int{800}},
+        {"no widths", 1000, nil, nil},
+    }
+
+    for _, tt := range tests {
+        got := variantWidths(tt.width, tt.widths)
+        if !slices.Equal(got, tt.want) {
+            t.Errorf("%s: variantWidths(%d, %v) = %v, want %v", tt.name, tt.width, tt.widths, got, tt.want)
+        }
+        for _, w := range got {
+            if w > tt.width {
+                t.Errorf("%s: variant %d is wider than the %d source", tt.name, w, tt.width)
+            }
+        }
+    }
+}
+
+// pngWithSize encodes a small PNG and rewrites its header to claim w x h, so
+// only decoding the header sees the claimed size.
+func pngWithSize(t *testing.T, w, h uint32) []byte {
+    t.Helper()
+
+    var buf bytes.Buffer
+    if err := png.Encode(&buf, solid(1, 1)); err != nil {
+        t.Fatal(err)
+    }
+    data := buf.Bytes()
+
+    // signature (8), IHDR length (4), "IHDR" (4), width, height
+    binary.BigEndian.PutUint32(data[16:], w)
+    binary.BigEndian.PutUint32(data[20:], h)
+    binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+
+    return data
+}
+
+func TestProcessMaxPixels(t *testing.T) {
+    tests := []struct {
+        name        string
+        data        []byte
+        wantTooBig  bool
+        wantErrType error
+    }{
+        {"over the limit", pngWithSize(t, 10000, 4001), true, ErrInvalidImage},
+        {"very wide", pngWithSize(t, 1<<30, 1), true, ErrInvalidImage},
+        {"not an image", []byte("GIF89a not really"), false, ErrUnsupportedType},
+    }
+
+    for _, tt := range tests {
+        _, _, err := Process(tt.data, DefaultWidths)
+        if !errors.Is(err, tt.wantErrType) {
+            t.Errorf("%s: err = %v, want %v", tt.name, err, tt.wantErrType)
+            continue
+        }
+
+        if tooBig := strings.Contains(err.Error(), "too large"); tooBig != tt.wantTooBig {
+            t.Errorf("%s: err = %v, want the size check to fire: %v", tt.name, err, tt.wantTooBig)
+        }
+    }
+}
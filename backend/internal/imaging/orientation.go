@@ -0,0 +1,132 @@
+package imaging
+
+import (
+    "encoding/binary"
+    "image"
+    "image/draw"
+)
+
+const orientationTag = 0x0112
+
+// jpegOrientation reads the EXIF orientation (1 to 8) from a JPEG, 1 when
+// there is none.
+func jpegOrientation(data []byte) int {
+    if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+        return 1
+    }
+
+    for i := 2; i+4 <= len(data); {
+        if data[i] != 0xFF {
+            return 1
+        }
+
+        marker := data[i+1]
+        // start of scan, the metadata segments are over
+        if marker == 0xDA {
+            return 1
+        }
+
+        length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+        if length < 2 || i+2+length > len(data) {
+            return 1
+        }
+
+        segment := data[i+4 : i+2+length]
+        if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+            return tiffOrientation(segment[6:])
+        }
+
+        i += 2 + length
+    }
+
+    return 1
+}
+
+// tiffOrientation finds the orientation tag in the first IFD of an EXIF TIFF
+// block.
+func tiffOrientation(tiff []byte) int {
+    if len(tiff) < 8 {
+        return 1
+    }
+
+    var order binary.ByteOrder
+    switch string(tiff[:2]) {
+    case "II":
+        order = binary.LittleEndian
+    case "MM":
+        order = binary.BigEndian
+    default:
+        return 1
+    }
+
+    offset := int(order.Uint32(tiff[4:8]))
+    if offset < 8 || offset+2 > len(tiff) {
+        return 1
+    }
+
+    count := int(order.Uint16(tiff[offset : offset+2]))
+    for i := 0; i < count; i++ {
+        entry := offset + 2 + i*12
+        if entry+12 > len(tiff) {
+            return 1
+        }
+
+        if order.Uint16(tiff[entry:entry+2]) != orientationTag {
+            continue
+        }
+
+        if v := int(order.Uint16(tiff[entry+8 : entry+10])); v >= 1 && v <= 8 {
+            return v
+        }
+        return 1
+    }
+
+    return 1
+}
+
+// orient applies an EXIF orientation to img, so it displays upright once the
+// EXIF data is gone.
+func orient(img image.Image, orientation int) image.Image {
+    if orientation <= 1 || orientation > 8 {
+        return img
+    }
+
+    b := img.Bounds()
+    src := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+    draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+
+    w, h := b.Dx(), b.Dy()
+    dw, dh := w, h
+    if orientation >= 5 {
+        dw, dh = h, w
+    }
+    dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+
+    for y := 0; y < h; y++ {
+        for x := 0; x < w; x++ {
+            var dx, dy int
+            switch orientation {
+            case 2:
+                dx, dy = w-1-x, y
+            case 3:
+                dx, dy = w-1-x, h-1-y
+            case 4:
+                dx, dy = x, h-1-y
+            case 5:
+                dx, dy = y, x
+            case 6:
+                dx, dy = h-1-y, x
+            case 7:
+                dx, dy = h-1-y, w-1-x
+            case 8:
+                dx, dy = y, w-1-x
+            }
+
+            si := src.PixOffset(x, y)
+            di := dst.PixOffset(dx, dy)
+            copy(dst.Pix[di:di+4], src.Pix[si:si+4])
+        }
+    }
+
+    return dst
+}
@@ -1,15 +1,20 @@
 package middleware
 
 import (
+    "crypto/subtle"
     "net/http"
 
     "github.com/labstack/echo/v4"
 )
 
-func APIKeyAuth(apiKey string) echo.MiddlewareFunc {
+const adminContextKey = "admin"
+
+// APIKeyAuth accepts requests whose X-API-Key is one of keys, which maps each
+// key to its admin's name. The name is available to handlers via AdminName.
+func APIKeyAuth(keys map[string]string) echo.MiddlewareFunc {
     return func(next echo.HandlerFunc) echo.HandlerFunc {
         return func(c echo.Context) error {
-            if apiKey == "" {
+            if len(keys) == 0 {
                 return echo.NewHTTPError(http.StatusInternalServerError, "admin API key not configured")
             }
 
@@ -18,11 +23,25 @@ func APIKeyAuth(apiKey string) echo.MiddlewareFunc {
                 return echo.NewHTTPError(http.StatusUnauthorized, "missing API key")
             }
 
-            if key != apiKey {
+            name := ""
+            for k, n := range keys {
+                if subtle.ConstantTimeCompare([]byte(key), []byte(k)) == 1 {
+                    name = n
+                }
+            }
+            if name == "" {
                 return echo.NewHTTPError(http.StatusUnauthorized, "invalid API key")
             }
 
+            c.Set(adminContextKey, name)
             return next(c)
         }
     }
 }
+
+// AdminName returns the name of the admin whose key authenticated the
+// request, or "" outside the admin routes.
+func AdminName(c echo.Context) string {
+    name, _ := c.Get(adminContextKey).(string)
+    return name
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+    "encoding/json"
+    "time"
+
+    "github.com/aidantrabs/kultur/backend/internal/db"
+    "github.com/aidantrabs/kultur/backend/internal/repository"
+)
+
+// Festival is a festival as the public site sees it.
+type Festival struct {
+    ID               string          `json:"id"`
+    Slug             string          `json:"slug"`
+    Name             string          `json:"name"`
+    DateType         string          `json:"dateType"`
+    UsualMonth       *string         `json:"usualMonth"`
+    Date2026Start    *string         `json:"date2026Start"`
+    Date2026End      *string         `json:"date2026End"`
+    Region           string          `json:"region"`
+    HeritageType     string          `json:"heritageType"`
+    FestivalType     string          `json:"festivalType"`
+    Summary          string          `json:"summary"`
+    Story            *string         `json:"story"`
+    WhatToExpect     *string         `json:"whatToExpect"`
+    HowToParticipate *string         `json:"howToParticipate"`
+    PracticalInfo    *string         `json:"practicalInfo"`
+    CoverImageURL    *string         `json:"coverImageUrl"`
+    GalleryImages    json.RawMessage `json:"galleryImages"`
+    VideoEmbeds      json.RawMessage `json:"videoEmbeds"`
+    IsPublished      bool            `json:"isPublished"`
+    CreatedAt        *time.Time      `json:"createdAt"`
+    UpdatedAt        *time.Time      `json:"updatedAt"`
+}
+
+func NewFestival(f db.Festival) Festival {
+    return Festival{
+        ID:               id(f.ID),
+        Slug:             f.Slug,
+        Name:             f.Name,
+        DateType:         f.DateType,
+        UsualMonth:       text(f.UsualMonth),
+        Date2026Start:    date(f.Date2026Start),
+        Date2026End:      date(f.Date2026End),
+        Region:           f.Region,
+        HeritageType:     f.HeritageType,
+        FestivalType:     f.FestivalType,
+        Summary:          f.Summary,
+        Story:            text(f.Story),
+        WhatToExpect:     text(f.WhatToExpect),
+        HowToParticipate: text(f.HowToParticipate),
+        PracticalInfo:    text(f.PracticalInfo),
+        CoverImageURL:    text(f.CoverImageUrl),
+        GalleryImages:    jsonArray(f.GalleryImages),
+        VideoEmbeds:      jsonArray(f.VideoEmbeds),
+        IsPublished:      f.IsPublished.Bool,
+        CreatedAt:        timestamp(f.CreatedAt),
+        UpdatedAt:        timestamp(f.UpdatedAt),
+    }
+}
+
+// FestivalListItem is a festival in the public festival list.
+type FestivalListItem struct {
+    Festival
+    NextStartDate *string `json:"nextStartDate"`
+}
+
+func NewFestivalListItem(r repository.ListPublishedFestivalsRow) FestivalListItem {
+    return FestivalListItem{
+        Festival:      NewFestival(r.Festival),
+        NextStartDate: date(r.NextStartDate),
+    }
+}
+
+// AdminFestival adds the fields only admins work with.
+type AdminFestival struct {
+    Festival
+    Recurrence json.RawMessage `json:"recurrence"`
+}
+
+func NewAdminFestival(f db.Festival) AdminFestival {
+    return AdminFestival{
+        Festival:   NewFestival(f),
+        Recurrence: jsonObject(f.Recurrence),
+    }
+}
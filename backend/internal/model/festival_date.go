@@ -0,0 +1,94 @@
+package model
+
+import (
+    "time"
+
+    "github.com/aidantrabs/kultur/backend/internal/db"
+)
+
+// FestivalDate is when a festival falls in one year.
+type FestivalDate struct {
+    ID          string     `json:"id"`
+    FestivalID  string     `json:"festivalId"`
+    Year        int32      `json:"year"`
+    StartDate   *string    `json:"startDate"`
+    EndDate     *string    `json:"endDate"`
+    IsTentative bool       `json:"isTentative"`
+    Source      string     `json:"source"`
+    CreatedAt   *time.Time `json:"createdAt"`
+}
+
+func NewFestivalDate(d db.FestivalDate) FestivalDate {
+    return FestivalDate{
+        ID:          id(d.ID),
+        FestivalID:  id(d.FestivalID),
+        Year:        d.Year,
+        StartDate:   date(d.StartDate),
+        EndDate:     date(d.EndDate),
+        IsTentative: d.IsTentative.Bool,
+        Source:      d.Source,
+        CreatedAt:   timestamp(d.CreatedAt),
+    }
+}
+
+// CalendarEntry is a festival date with enough of its festival to show it
+// in a calendar or upcoming list.
+type CalendarEntry struct {
+    FestivalDate
+    Slug         string `json:"slug"`
+    Name         string `json:"name"`
+    Region       string `json:"region"`
+    HeritageType string `json:"heritageType"`
+    FestivalType string `json:"festivalType"`
+    Summary      string `json:"summary"`
+}
+
+func NewUpcomingCalendarEntry(r db.ListUpcomingFestivalDatesRow) CalendarEntry {
+    return CalendarEntry{
+        FestivalDate: NewFestivalDate(db.FestivalDate{
+            ID:          r.ID,
+            FestivalID:  r.FestivalID,
+            Year:        r.Year,
+            StartDate:   r.StartDate,
+            EndDate:     r.EndDate,
+            IsTentative: r.IsTentative,
+            CreatedAt:   r.CreatedAt,
+            Source:      r.Source,
+        }),
+        Slug:         r.Slug,
+        Name:         r.Name,
+        Region:       r.Region,
+        HeritageType: r.HeritageType,
+        FestivalType: r.FestivalType,
+        Summary:      r.Summary,
+    }
+}
+
+func NewYearCalendarEntry(r db.ListFestivalDatesByYearRow) CalendarEntry {
+    return NewUpcomingCalendarEntry(db.ListUpcomingFestivalDatesRow(r))
+}
+
+// TentativeFestivalDate is an estimated date waiting for an admin to
+// confirm it.
+type TentativeFestivalDate struct {
+    FestivalDate
+    Slug string `json:"slug"`
+    Name string `json:"name"`
+}
+
+func NewTentativeFestivalDate(r db.ListTentativeFestivalDatesRow) TentativeFestivalDate {
+    return TentativeFestivalDate{
+        FestivalDate: NewFestivalDate(db.FestivalDate{
+            ID:          r.ID,
+            FestivalID:  r.FestivalID,
+            Year:        r.Year,
+            StartDate:   r.StartDate,
+            EndDate:     r.EndDate,
+            IsTentative: r.IsTentative,
+            CreatedAt:   r.CreatedAt,
+            Source:      r.Source,
+        }),
+        Slug: r.Slug,
+        Name: r.Name,
+    }
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/aidantrabs/kultur/backend/internal/db"
+)
+
+// MediaAsset is an uploaded image. URL is its widest variant; SrcSet lists
+// every variant for an <img srcset>.
+type MediaAsset struct {
+    ID               string         `json:"id"`
+    OriginalFilename string         `json:"originalFilename"`
+    ContentType      string         `json:"contentType"`
+    SizeBytes        int64          `json:"sizeBytes"`
+    Width            int32          `json:"width"`
+    Height           int32          `json:"height"`
+    URL              string         `json:"url"`
+    SrcSet           string         `json:"srcSet"`
+    Variants         []MediaVariant `json:"variants"`
+    CreatedAt        *time.Time     `json:"createdAt"`
+}
+
+type MediaVariant struct {
+    Width       int32  `json:"width"`
+    Height      int32  `json:"height"`
+    ContentType string `json:"contentType"`
+    SizeBytes   int64  `json:"sizeBytes"`
+    URL         string `json:"url"`
+}
+
+// NewMediaAsset takes the variants narrowest first and url, which maps a
+// storage key to its public URL.
+func NewMediaAsset(a db.MediaAsset, variants []db.MediaVariant, url func(key string) string) MediaAsset {
+    asset := MediaAsset{
+        ID:               id(a.ID),
+        OriginalFilename: a.OriginalFilename,
+        ContentType:      a.ContentType,
+        SizeBytes:        a.SizeBytes,
+        Width:            a.Width,
+        Height:           a.Height,
+        Variants:         make([]MediaVariant, 0, len(variants)),
+        CreatedAt:        timestamp(a.CreatedAt),
+    }
+
+    srcset := make([]string, 0, len(variants))
+    for _, v := range variants {
+        u := url(v.StorageKey)
+        asset.Variants = append(asset.Variants, MediaVariant{
+            Width:       v.Width,
+            Height:      v.Height,
+            ContentType: v.ContentType,
+            SizeBytes:   v.SizeBytes,
+            URL:         u,
+        })
+        srcset = append(srcset, u+" "+strconv.Itoa(int(v.Width))+"w")
+        asset.URL = u
+    }
+    asset.SrcSet = strings.Join(srcset, ", ")
+
+    return asset
+}
@@ -0,0 +1,95 @@
+package model
+
+import (
+    "encoding/json"
+    "time"
+
+    "github.com/aidantrabs/kultur/backend/internal/db"
+    "github.com/jackc/pgx/v5/pgtype"
+)
+
+// Memory is an approved memory as the public site sees it. The author's
+// email and moderation details are left out.
+type Memory struct {
+    ID           string     `json:"id"`
+    FestivalID   string     `json:"festivalId"`
+    AuthorName   *string    `json:"authorName"`
+    Content      string     `json:"content"`
+    YearOfMemory *string    `json:"yearOfMemory"`
+    SubmittedAt  *time.Time `json:"submittedAt"`
+}
+
+func NewMemory(m db.Memory) Memory {
+    return Memory{
+        ID:           id(m.ID),
+        FestivalID:   id(m.FestivalID),
+        AuthorName:   text(m.AuthorName),
+        Content:      m.Content,
+        YearOfMemory: text(m.YearOfMemory),
+        SubmittedAt:  timestamp(m.SubmittedAt),
+    }
+}
+
+// AdminMemory is a memory with its author's email and moderation state.
+type AdminMemory struct {
+    Memory
+    AuthorEmail      *string         `json:"authorEmail"`
+    Status           string          `json:"status"`
+    NotifyAuthor     bool            `json:"notifyAuthor"`
+    ScreeningScore   int32           `json:"screeningScore"`
+    ScreeningResults json.RawMessage `json:"screeningResults"`
+    Flagged          bool            `json:"flagged"`
+}
+
+func NewAdminMemory(m db.Memory) AdminMemory {
+    return AdminMemory{
+        Memory:           NewMemory(m),
+        AuthorEmail:      text(m.AuthorEmail),
+        Status:           m.Status.String,
+        NotifyAuthor:     m.NotifyAuthor,
+        ScreeningScore:   m.ScreeningScore,
+        ScreeningResults: jsonArray(m.ScreeningResults),
+        Flagged:          m.Flagged,
+    }
+}
+
+// MemoryReview is one status change in a memory's history.
+type MemoryReview struct {
+    ID            string     `json:"id"`
+    MemoryID      string     `json:"memoryId"`
+    FromStatus    *string    `json:"fromStatus"`
+    ToStatus      string     `json:"toStatus"`
+    Reviewer      string     `json:"reviewer"`
+    Reason        *string    `json:"reason"`
+    Notes         *string    `json:"notes"`
+    AuthorMessage *string    `json:"authorMessage"`
+    CreatedAt     *time.Time `json:"createdAt"`
+}
+
+func NewMemoryReview(r db.MemoryReview) MemoryReview {
+    return MemoryReview{
+        ID:            id(r.ID),
+        MemoryID:      id(r.MemoryID),
+        FromStatus:    text(r.FromStatus),
+        ToStatus:      r.ToStatus,
+        Reviewer:      r.Reviewer,
+        Reason:        text(r.Reason),
+        Notes:         text(r.Notes),
+        AuthorMessage: text(r.AuthorMessage),
+        CreatedAt:     timestamp(r.CreatedAt),
+    }
+}
+
+// BulkReviewResult lists which memories a bulk review changed and which ids
+// matched no memory.
+type BulkReviewResult struct {
+    Reviewed []string `json:"reviewed"`
+    NotFound []string `json:"notFound"`
+}
+
+func NewBulkReviewResult(reviewed, notFound []pgtype.UUID) BulkReviewResult {
+    return BulkReviewResult{
+        Reviewed: Map(reviewed, id),
+        NotFound: Map(notFound, id),
+    }
+}
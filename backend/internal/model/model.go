@@ -0,0 +1,77 @@
+// Package model holds the JSON shapes the API responds with. They are kept
+// apart from the sqlc models in internal/db so that columns can be added to
+// the database without ending up in public responses, and so nullable
+// columns serialize as plain values or null.
+package model
+
+import (
+    "encoding/json"
+    "time"
+
+    "github.com/google/uuid"
+    "github.com/jackc/pgx/v5/pgtype"
+)
+
+const dateLayout = "2006-01-02"
+
+// Map converts every row of a query result, returning an empty slice rather
+// than nil so lists always serialize as [].
+func Map[T, U any](rows []T, f func(T) U) []U {
+    out := make([]U, len(rows))
+    for i, row := range rows {
+        out[i] = f(row)
+    }
+
+    return out
+}
+
+func id(u pgtype.UUID) string {
+    if !u.Valid {
+        return ""
+    }
+
+    return uuid.UUID(u.Bytes).String()
+}
+
+func text(t pgtype.Text) *string {
+    if !t.Valid {
+        return nil
+    }
+
+    return &t.String
+}
+
+func date(d pgtype.Date) *string {
+    if !d.Valid {
+        return nil
+    }
+
+    s := d.Time.Format(dateLayout)
+    return &s
+}
+
+func timestamp(t pgtype.Timestamptz) *time.Time {
+    if !t.Valid {
+        return nil
+    }
+
+    return &t.Time
+}
+
+// jsonArray passes a JSONB array column through as JSON, [] when empty.
+func jsonArray(raw []byte) json.RawMessage {
+    if len(raw) == 0 || !json.Valid(raw) {
+        return json.RawMessage("[]")
+    }
+
+    return json.RawMessage(raw)
+}
+
+// jsonObject passes a nullable JSONB object column through as JSON.
+func jsonObject(raw []byte) json.RawMessage {
+    if len(raw) == 0 || !json.Valid(raw) {
+        return json.RawMessage("null")
+    }
+
+    return json.RawMessage(raw)
+}
@@ -0,0 +1,64 @@
+package model
+
+import "github.com/aidantrabs/kultur/backend/internal/db"
+
+type SearchResults struct {
+    Query     string           `json:"query"`
+    Festivals []SearchFestival `json:"festivals"`
+    Memories  []SearchMemory   `json:"memories"`
+}
+
+func NewSearchResults(query string, festivals []db.SearchFestivalsRow, memories []db.SearchMemoriesRow) SearchResults {
+    return SearchResults{
+        Query:     query,
+        Festivals: Map(festivals, NewSearchFestival),
+        Memories:  Map(memories, NewSearchMemory),
+    }
+}
+
+// SearchFestival is a festival search hit. Snippet is HTML with the matches
+// wrapped in <mark>.
+type SearchFestival struct {
+    ID      string  `json:"id"`
+    Slug    string  `json:"slug"`
+    Name    string  `json:"name"`
+    Summary string  `json:"summary"`
+    Rank    float32 `json:"rank"`
+    Snippet string  `json:"snippet"`
+}
+
+func NewSearchFestival(r db.SearchFestivalsRow) SearchFestival {
+    return SearchFestival{
+        ID:      id(r.ID),
+        Slug:    r.Slug,
+        Name:    r.Name,
+        Summary: r.Summary,
+        Rank:    r.Rank,
+        Snippet: r.Snippet,
+    }
+}
+
+// SearchMemory is a memory search hit, with the festival it belongs to.
+type SearchMemory struct {
+    ID           string  `json:"id"`
+    FestivalID   string  `json:"festivalId"`
+    FestivalSlug string  `json:"festivalSlug"`
+    FestivalName string  `json:"festivalName"`
+    AuthorName   *string `json:"authorName"`
+    YearOfMemory *string `json:"yearOfMemory"`
+    Rank         float32 `json:"rank"`
+    Snippet      string  `json:"snippet"`
+}
+
+func NewSearchMemory(r db.SearchMemoriesRow) SearchMemory {
+    return SearchMemory{
+        ID:           id(r.ID),
+        FestivalID:   id(r.FestivalID),
+        FestivalSlug: r.FestivalSlug,
+        FestivalName: r.FestivalName,
+        AuthorName:   text(r.AuthorName),
+        YearOfMemory: text(r.YearOfMemory),
+        Rank:         r.Rank,
+        Snippet:      r.Snippet,
+    }
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+    "encoding/json"
+    "time"
+
+    "github.com/aidantrabs/kultur/backend/internal/db"
+)
+
+// AdminSubscription is a subscription for the admin list. Its tokens act as
+// the subscriber's credentials, so they are never included.
+type AdminSubscription struct {
+    ID                    string          `json:"id"`
+    Email                 string          `json:"email"`
+    DigestWeekly          bool            `json:"digestWeekly"`
+    FestivalReminders     json.RawMessage `json:"festivalReminders"`
+    Confirmed             bool            `json:"confirmed"`
+    ConfirmationExpiresAt *time.Time      `json:"confirmationExpiresAt"`
+    CreatedAt             *time.Time      `json:"createdAt"`
+}
+
+func NewAdminSubscription(s db.Subscription) AdminSubscription {
+    return AdminSubscription{
+        ID:                    id(s.ID),
+        Email:                 s.Email,
+        DigestWeekly:          s.DigestWeekly.Bool,
+        FestivalReminders:     jsonArray(s.FestivalReminders),
+        Confirmed:             s.Confirmed.Bool,
+        ConfirmationExpiresAt: timestamp(s.ConfirmationExpiresAt),
+        CreatedAt:             timestamp(s.CreatedAt),
+    }
+}
@@ -0,0 +1,186 @@
+// Package repository holds queries that sqlc cannot generate, such as those
+// whose SQL depends on the filters in a request.
+package repository
+
+import (
+    "context"
+    "fmt"
+    "strings"
+
+    "github.com/aidantrabs/kultur/backend/internal/db"
+    "github.com/jackc/pgx/v5/pgtype"
+)
+
+// FestivalRepository runs the dynamic festival queries on the same
+// connection or transaction the generated queries use.
+type FestivalRepository struct {
+    db db.DBTX
+}
+
+func NewFestivalRepository(conn db.DBTX) *FestivalRepository {
+    return &FestivalRepository{db: conn}
+}
+
+const (
+    FestivalSortName      = "name"
+    FestivalSortNextDate  = "next_date"
+    FestivalSortCreatedAt = "created_at"
+)
+
+// FestivalCursor is the position of the last row of a page. Only the fields
+// used by the requested sort need to be set.
+type FestivalCursor struct {
+    ID            pgtype.UUID
+    Name          string
+    NextStartDate pgtype.Date
+    CreatedAt     pgtype.Timestamptz
+}
+
+// ListPublishedFestivalsParams combines any of the public festival filters.
+// Zero values leave a filter out.
+type ListPublishedFestivalsParams struct {
+    Region       string
+    HeritageType string
+    FestivalType string
+    DateType     string
+    Month        int
+    HasUpcoming  pgtype.Bool
+    From         pgtype.Date
+    To           pgtype.Date
+    Sort         string
+    After        *FestivalCursor
+    Limit        int32
+}
+
+type ListPublishedFestivalsRow struct {
+    db.Festival
+    NextStartDate pgtype.Date `json:"nextStartDate"`
+}
+
+const listPublishedFestivalsSelect = `SELECT f.id, f.slug, f.name, f.date_type, f.region, f.heritage_type, f.festival_type, f.summary, f.story, f.what_to_expect, f.how_to_participate, f.practical_info, f.cover_image_url, f.gallery_images, f.video_embeds, f.is_published, f.created_at, f.usual_month, f.date_2026_start, f.date_2026_end, f.recurrence, f.updated_at, nd.next_start_date
+FROM festivals f
+LEFT JOIN LATERAL (
+    SELECT MIN(fd.start_date) AS next_start_date
+    FROM festival_dates fd
+    WHERE fd.festival_id = f.id AND COALESCE(fd.end_date, fd.start_date) >= CURRENT_DATE
+) nd ON true`
+
+// ListPublishedFestivals builds its WHERE and ORDER BY clauses from the
+// filters and sort that are set. It fetches up to Limit rows after the cursor.
+func (r *FestivalRepository) ListPublishedFestivals(ctx context.Context, arg ListPublishedFestivalsParams) ([]ListPublishedFestivalsRow, error) {
+    var (
+        where = []string{"f.is_published = true"}
+        args  []any
+    )
+
+    bind := func(v any) string {
+        args = append(args, v)
+        return fmt.Sprintf("$%d", len(args))
+    }
+
+    if arg.Region != "" {
+        where = append(where, "f.region = "+bind(arg.Region))
+    }
+    if arg.HeritageType != "" {
+        where = append(where, "f.heritage_type = "+bind(arg.HeritageType))
+    }
+    if arg.FestivalType != "" {
+        where = append(where, "f.festival_type = "+bind(arg.FestivalType))
+    }
+    if arg.DateType != "" {
+        where = append(where, "f.date_type = "+bind(arg.DateType))
+    }
+    if arg.HasUpcoming.Valid {
+        if arg.HasUpcoming.Bool {
+            where = append(where, "nd.next_start_date IS NOT NULL")
+        } else {
+            where = append(where, "nd.next_start_date IS NULL")
+        }
+    }
+
+    // month and date range must match the same festival date
+    var dateConds []string
+    if arg.Month != 0 {
+        dateConds = append(dateConds, "EXTRACT(MONTH FROM fd.start_date)::int = "+bind(arg.Month))
+    }
+    if arg.From.Valid {
+        dateConds = append(dateConds, "COALESCE(fd.end_date, fd.start_date) >= "+bind(arg.From))
+    }
+    if arg.To.Valid {
+        dateConds = append(dateConds, "fd.start_date <= "+bind(arg.To))
+    }
+    if len(dateConds) > 0 {
+        where = append(where, "EXISTS (SELECT 1 FROM festival_dates fd WHERE fd.festival_id = f.id AND "+strings.Join(dateConds, " AND ")+")")
+    }
+
+    var orderBy string
+    switch arg.Sort {
+    case FestivalSortNextDate:
+        orderBy = "nd.next_start_date ASC NULLS LAST, f.name ASC, f.id ASC"
+        if c := arg.After; c != nil {
+            if c.NextStartDate.Valid {
+                where = append(where, fmt.Sprintf("((nd.next_start_date, f.name, f.id) > (%s, %s, %s) OR nd.next_start_date IS NULL)",
+                    bind(c.NextStartDate), bind(c.Name), bind(c.ID)))
+            } else {
+                where = append(where, fmt.Sprintf("(nd.next_start_date IS NULL AND (f.name, f.id) > (%s, %s))",
+                    bind(c.Name), bind(c.ID)))
+            }
+        }
+    case FestivalSortCreatedAt:
+        orderBy = "f.created_at DESC, f.id DESC"
+        if c := arg.After; c != nil {
+            where = append(where, fmt.Sprintf("(f.created_at, f.id) < (%s, %s)", bind(c.CreatedAt), bind(c.ID)))
+        }
+    default:
+        orderBy = "f.name ASC, f.id ASC"
+        if c := arg.After; c != nil {
+            where = append(where, fmt.Sprintf("(f.name, f.id) > (%s, %s)", bind(c.Name), bind(c.ID)))
+        }
+    }
+
+    query := listPublishedFestivalsSelect +
+        "\nWHERE " + strings.Join(where, " AND ") +
+        "\nORDER BY " + orderBy +
+        "\nLIMIT " + bind(arg.Limit)
+
+    rows, err := r.db.Query(ctx, query, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    items := []ListPublishedFestivalsRow{}
+    for rows.Next() {
+        var i ListPublishedFestivalsRow
+        if err := rows.Scan(
+            &i.ID,
+            &i.Slug,
+            &i.Name,
+            &i.DateType,
+            &i.Region,
+            &i.HeritageType,
+            &i.FestivalType,
+            &i.Summary,
+            &i.Story,
+            &i.WhatToExpect,
+            &i.HowToParticipate,
+            &i.PracticalInfo,
+            &i.CoverImageUrl,
+            &i.GalleryImages,
+            &i.VideoEmbeds,
+            &i.IsPublished,
+            &i.CreatedAt,
+            &i.UsualMonth,
+            &i.Date2026Start,
+            &i.Date2026End,
+            &i.Recurrence,
+            &i.UpdatedAt,
+            &i.NextStartDate,
+        ); err != nil {
+            return nil, err
+        }
+        items = append(items, i)
+    }
+
+    return items, rows.Err()
+}
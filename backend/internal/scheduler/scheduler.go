@@ -0,0 +1,155 @@
+package scheduler
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "time"
+
+    "github.com/aidantrabs/kultur/backend/internal/db"
+    "github.com/jackc/pgx/v5"
+    "github.com/jackc/pgx/v5/pgtype"
+)
+
+// PeriodFunc returns the key of the run window that contains now, or an
+// empty string if the job is not due yet.
+type PeriodFunc func(now time.Time) string
+
+type Job struct {
+    Name   string
+    Period PeriodFunc
+    Run    func(ctx context.Context) error
+}
+
+// runTimeout bounds how long a single run may take. A claim left unfinished
+// for twice that long belongs to an instance that died mid-run and can be
+// claimed again.
+const runTimeout = 30 * time.Minute
+
+type periodKey struct{}
+
+// Period returns the key of the period the running job was claimed for.
+func Period(ctx context.Context) string {
+    period, _ := ctx.Value(periodKey{}).(string)
+    return period
+}
+
+type Scheduler struct {
+    queries  *db.Queries
+    interval time.Duration
+    jobs     []Job
+}
+
+func New(queries *db.Queries, interval time.Duration) *Scheduler {
+    return &Scheduler{
+        queries:  queries,
+        interval: interval,
+    }
+}
+
+func (s *Scheduler) Register(job Job) {
+    s.jobs = append(s.jobs, job)
+}
+
+// Start checks every registered job once immediately and then on every
+// interval until ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+    go func() {
+        ticker := time.NewTicker(s.interval)
+        defer ticker.Stop()
+
+        for {
+            s.tick(ctx, time.Now())
+
+            select {
+            case <-ctx.Done():
+                return
+            case <-ticker.C:
+            }
+        }
+    }()
+}
+
+func (s *Scheduler) tick(ctx context.Context, now time.Time) {
+    for _, job := range s.jobs {
+        period := job.Period(now)
+        if period == "" {
+            continue
+        }
+
+        if err := s.runOnce(ctx, job, period); err != nil {
+            log.Printf("scheduler: job %s (%s) failed: %v", job.Name, period, err)
+        }
+    }
+}
+
+// runOnce claims the job run for the period before executing it, so only one
+// instance runs it at a time. A successful run is never repeated; a failed
+// run, or one that outlived its lease, is claimed again on the next tick.
+func (s *Scheduler) runOnce(ctx context.Context, job Job, period string) error {
+    run, err := s.queries.ClaimJobRun(ctx, db.ClaimJobRunParams{
+        JobName:      job.Name,
+        PeriodKey:    period,
+        LeaseSeconds: int32(2 * runTimeout / time.Second),
+    })
+    if errors.Is(err, pgx.ErrNoRows) {
+        return nil // already ran or running elsewhere
+    }
+    if err != nil {
+        return fmt.Errorf("failed to claim run: %w", err)
+    }
+
+    log.Printf("scheduler: running %s (%s)", job.Name, period)
+
+    runCtx, cancel := context.WithTimeout(context.WithValue(ctx, periodKey{}, period), runTimeout)
+    jobErr := job.Run(runCtx)
+    cancel()
+
+    var errText pgtype.Text
+    if jobErr != nil {
+        errText = pgtype.Text{String: jobErr.Error(), Valid: true}
+    }
+
+    // record the outcome even during shutdown, so the claim does not sit
+    // until its lease runs out
+    if err := s.queries.FinishJobRun(context.WithoutCancel(ctx), db.FinishJobRunParams{
+        ID:    run.ID,
+        Error: errText,
+    }); err != nil {
+        return fmt.Errorf("failed to record run: %w", err)
+    }
+
+    return jobErr
+}
+
+// Weekly is due from the given weekday and hour onwards, once per ISO week.
+func Weekly(weekday time.Weekday, hour int, loc *time.Location) PeriodFunc {
+    return func(now time.Time) string {
+        now = now.In(loc)
+
+        // days since the start of the ISO week (monday)
+        offset := (int(now.Weekday()) + 6) % 7
+        due := (int(weekday) + 6) % 7
+
+        if offset < due || (offset == due && now.Hour() < hour) {
+            return ""
+        }
+
+        year, week := now.ISOWeek()
+
+        return fmt.Sprintf("%d-W%02d", year, week)
+    }
+}
+
+// Daily is due from the given hour onwards, once per calendar day.
+func Daily(hour int, loc *time.Location) PeriodFunc {
+    return func(now time.Time) string {
+        now = now.In(loc)
+        if now.Hour() < hour {
+            return ""
+        }
+
+        return now.Format("2006-01-02")
+    }
+}
@@ -0,0 +1,84 @@
+package scheduler
+
+import (
+    "testing"
+    "time"
+)
+
+func TestWeekly(t *testing.T) {
+    loc := time.FixedZone("AST", -4*60*60)
+    period := Weekly(time.Monday, 8, loc)
+
+    tests := []struct {
+        name string
+        now  time.Time
+        want string
+    }{
+        {"monday midnight", time.Date(2025, time.March, 3, 0, 0, 0, 0, loc), ""},
+        {"just before the hour", time.Date(2025, time.March, 3, 7, 59, 59, 0, loc), ""},
+        {"on the hour", time.Date(2025, time.March, 3, 8, 0, 0, 0, loc), "2025-W10"},
+        {"later in the week", time.Date(2025, time.March, 7, 13, 0, 0, 0, loc), "2025-W10"},
+        {"sunday night", time.Date(2025, time.March, 9, 23, 59, 59, 0, loc), "2025-W10"},
+        {"next monday midnight", time.Date(2025, time.March, 10, 0, 0, 0, 0, loc), ""},
+        {"utc monday still sunday locally", time.Date(2025, time.March, 10, 2, 0, 0, 0, time.UTC), "2025-W10"},
+        {"utc hour before local due", time.Date(2025, time.March, 10, 11, 59, 0, 0, time.UTC), ""},
+        {"utc hour at local due", time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC), "2025-W11"},
+        {"week 1 starting in december", time.Date(2024, time.December, 30, 8, 0, 0, 0, loc), "2025-W01"},
+        {"new year in week 1", time.Date(2025, time.January, 1, 9, 0, 0, 0, loc), "2025-W01"},
+        {"week 53", time.Date(2020, time.December, 28, 8, 0, 0, 0, loc), "2020-W53"},
+        {"january in the previous year's week 53", time.Date(2021, time.January, 3, 12, 0, 0, 0, loc), "2020-W53"},
+    }
+
+    for _, tt := range tests {
+        if got := period(tt.now); got != tt.want {
+            t.Errorf("%s: Weekly(%s) = %q, want %q", tt.name, tt.now.Format(time.RFC3339), got, tt.want)
+        }
+    }
+}
+
+func TestWeeklyMidweek(t *testing.T) {
+    loc := time.FixedZone("AST", -4*60*60)
+    period := Weekly(time.Thursday, 9, loc)
+
+    tests := []struct {
+        now  time.Time
+        want string
+    }{
+        {time.Date(2025, time.March, 3, 10, 0, 0, 0, loc), ""},
+        {time.Date(2025, time.March, 6, 8, 59, 0, 0, loc), ""},
+        {time.Date(2025, time.March, 6, 9, 0, 0, 0, loc), "2025-W10"},
+        {time.Date(2025, time.March, 9, 0, 0, 0, 0, loc), "2025-W10"},
+    }
+
+    for _, tt := range tests {
+        if got := period(tt.now); got != tt.want {
+            t.Errorf("Weekly(%s) = %q, want %q", tt.now.Format(time.RFC3339), got, tt.want)
+        }
+    }
+}
+
+func TestDaily(t *testing.T) {
+    loc := time.FixedZone("AST", -4*60*60)
+    period := Daily(9, loc)
+
+    tests := []struct {
+        name string
+        now  time.Time
+        want string
+    }{
+        {"midnight", time.Date(2025, time.March, 3, 0, 0, 0, 0, loc), ""},
+        {"just before the hour", time.Date(2025, time.March, 3, 8, 59, 59, 0, loc), ""},
+        {"on the hour", time.Date(2025, time.March, 3, 9, 0, 0, 0, loc), "2025-03-03"},
+        {"last second of the day", time.Date(2025, time.March, 3, 23, 59, 59, 0, loc), "2025-03-03"},
+        {"utc next day still today locally", time.Date(2025, time.March, 4, 2, 0, 0, 0, time.UTC), "2025-03-03"},
+        {"utc hour at local due", time.Date(2025, time.March, 4, 13, 0, 0, 0, time.UTC), "2025-03-04"},
+        {"new year's eve", time.Date(2025, time.December, 31, 22, 0, 0, 0, loc), "2025-12-31"},
+        {"new year's day before the hour", time.Date(2026, time.January, 1, 8, 0, 0, 0, loc), ""},
+    }
+
+    for _, tt := range tests {
+        if got := period(tt.now); got != tt.want {
+            t.Errorf("%s: Daily(%s) = %q, want %q", tt.name, tt.now.Format(time.RFC3339), got, tt.want)
+        }
+    }
+}
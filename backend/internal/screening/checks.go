@@ -0,0 +1,135 @@
+package screening
+
+import (
+    "context"
+    "fmt"
+    "regexp"
+    "strings"
+    "unicode/utf8"
+)
+
+// RejectScore is a score high enough to reject a submission on its own with
+// the default thresholds.
+const RejectScore = 100
+
+var (
+    linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+    wordPattern = regexp.MustCompile(`[\p{L}\p{N}']+`)
+)
+
+// DefaultBannedWords are terms that only show up in spam.
+var DefaultBannedWords = []string{
+    "viagra", "cialis", "casino", "forex", "bitcoin", "crypto", "payday",
+    "seo", "backlink", "backlinks", "escort", "porn",
+}
+
+// Links scores content with more than Allowed links, PerLink for each extra
+// one. A link in the author name adds NameLink, since people rarely put one
+// there.
+type Links struct {
+    Allowed  int
+    PerLink  int
+    NameLink int
+}
+
+func (Links) Name() string { return "links" }
+
+func (c Links) Score(ctx context.Context, sub Submission) (int, string, error) {
+    var (
+        score   int
+        reasons []string
+    )
+
+    if n := len(linkPattern.FindAllString(sub.Content, -1)); n > c.Allowed {
+        score += (n - c.Allowed) * c.PerLink
+        reasons = append(reasons, fmt.Sprintf("%d links", n))
+    }
+
+    if linkPattern.MatchString(sub.AuthorName) {
+        score += c.NameLink
+        reasons = append(reasons, "link in author name")
+    }
+
+    return score, strings.Join(reasons, ", "), nil
+}
+
+// BannedWords scores PerWord for every distinct banned word in the content
+// or author name. Matching ignores case and only matches whole words.
+type BannedWords struct {
+    Words   []string
+    PerWord int
+}
+
+func (BannedWords) Name() string { return "banned_words" }
+
+func (c BannedWords) Score(ctx context.Context, sub Submission) (int, string, error) {
+    banned := make(map[string]bool, len(c.Words))
+    for _, w := range c.Words {
+        banned[strings.ToLower(w)] = true
+    }
+
+    var found []string
+    for _, w := range wordPattern.FindAllString(strings.ToLower(sub.Content+" "+sub.AuthorName), -1) {
+        if banned[w] {
+            found = append(found, w)
+            delete(banned, w)
+        }
+    }
+
+    if len(found) == 0 {
+        return 0, "", nil
+    }
+
+    return len(found) * c.PerWord, "banned words: " + strings.Join(found, ", "), nil
+}
+
+// Duplicate scores content whose ContentHash was already submitted. Seen
+// looks the hash up in storage.
+type Duplicate struct {
+    Seen   func(ctx context.Context, hash string) (bool, error)
+    Points int
+}
+
+func (Duplicate) Name() string { return "duplicate" }
+
+func (c Duplicate) Score(ctx context.Context, sub Submission) (int, string, error) {
+    seen, err := c.Seen(ctx, ContentHash(sub.Content))
+    if err != nil {
+        return 0, "", err
+    }
+
+    if !seen {
+        return 0, "", nil
+    }
+
+    return c.Points, "repeated content", nil
+}
+
+// Honeypot rejects any submission that filled in the honeypot field.
+type Honeypot struct{}
+
+func (Honeypot) Name() string { return "honeypot" }
+
+func (Honeypot) Score(ctx context.Context, sub Submission) (int, string, error) {
+    if sub.Honeypot == "" {
+        return 0, "", nil
+    }
+
+    return RejectScore, "honeypot field filled", nil
+}
+
+// Length rejects content longer than Max characters.
+type Length struct {
+    Max int
+}
+
+func (Length) Name() string { return "length" }
+
+func (c Length) Score(ctx context.Context, sub Submission) (int, string, error) {
+    n := utf8.RuneCountInString(sub.Content)
+    if n <= c.Max {
+        return 0, "", nil
+    }
+
+    return RejectScore, fmt.Sprintf("%d characters, max is %d", n, c.Max), nil
+}
@@ -0,0 +1,119 @@
+// Package screening scores memory submissions for spam and abuse before they
+// are stored.
+package screening
+
+import (
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "fmt"
+    "strings"
+)
+
+type Decision string
+
+const (
+    // Accept leaves the submission in the normal moderation queue.
+    Accept Decision = "accept"
+    // Flag moves the submission to the front of the moderation queue.
+    Flag Decision = "flag"
+    // Reject rejects the submission without a moderator.
+    Reject Decision = "reject"
+)
+
+// Submission is what the checks look at.
+type Submission struct {
+    Content     string
+    AuthorName  string
+    AuthorEmail string
+    // Honeypot is a form field hidden from people, so only bots fill it in.
+    Honeypot string
+}
+
+// Check is one screening rule. It returns a score, 0 when nothing looks
+// wrong, and a short reason for a non-zero score.
+type Check interface {
+    Name() string
+    Score(ctx context.Context, sub Submission) (score int, reason string, err error)
+}
+
+// Result is the outcome of one check that scored above 0.
+type Result struct {
+    Check  string `json:"check"`
+    Score  int    `json:"score"`
+    Reason string `json:"reason"`
+}
+
+// Verdict is the outcome of the whole pipeline. Score is the sum of every
+// check's score.
+type Verdict struct {
+    Score    int
+    Results  []Result
+    Decision Decision
+}
+
+// Reasons joins the reasons of every check that scored, for logs and review
+// records.
+func (v Verdict) Reasons() string {
+    reasons := make([]string, 0, len(v.Results))
+    for _, r := range v.Results {
+        reasons = append(reasons, fmt.Sprintf("%s (%d)", r.Reason, r.Score))
+    }
+
+    return strings.Join(reasons, "; ")
+}
+
+// Pipeline runs every check and turns the total score into a decision.
+type Pipeline struct {
+    checks      []Check
+    flagScore   int
+    rejectScore int
+}
+
+// NewPipeline returns a pipeline that flags submissions scoring flagScore or
+// more and rejects those scoring rejectScore or more.
+func NewPipeline(flagScore, rejectScore int, checks ...Check) *Pipeline {
+    return &Pipeline{
+        checks:      checks,
+        flagScore:   flagScore,
+        rejectScore: rejectScore,
+    }
+}
+
+// Screen runs the checks in order. An error from any check stops the
+// pipeline, so a submission is never accepted unscreened.
+func (p *Pipeline) Screen(ctx context.Context, sub Submission) (Verdict, error) {
+    verdict := Verdict{Results: []Result{}, Decision: Accept}
+
+    for _, check := range p.checks {
+        score, reason, err := check.Score(ctx, sub)
+        if err != nil {
+            return Verdict{}, fmt.Errorf("screening: %s: %w", check.Name(), err)
+        }
+
+        if score <= 0 {
+            continue
+        }
+
+        verdict.Score += score
+        verdict.Results = append(verdict.Results, Result{Check: check.Name(), Score: score, Reason: reason})
+    }
+
+    switch {
+    case verdict.Score >= p.rejectScore:
+        verdict.Decision = Reject
+    case verdict.Score >= p.flagScore:
+        verdict.Decision = Flag
+    }
+
+    return verdict, nil
+}
+
+// ContentHash identifies content regardless of case and whitespace, so
+// lightly edited reposts still match.
+func ContentHash(content string) string {
+    normalized := strings.Join(strings.Fields(strings.ToLower(content)), " ")
+    sum := sha256.Sum256([]byte(normalized))
+
+    return hex.EncodeToString(sum[:])
+}
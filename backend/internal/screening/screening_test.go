@@ -0,0 +1,210 @@
+package screening
+
+import (
+    "context"
+    "errors"
+    "strings"
+    "testing"
+)
+
+// fixed is a check that always returns the same score.
+type fixed struct {
+    name  string
+    score int
+    err   error
+}
+
+func (c fixed) Name() string { return c.name }
+
+func (c fixed) Score(ctx context.Context, sub Submission) (int, string, error) {
+    return c.score, c.name + " reason", c.err
+}
+
+func TestPipelineScreen(t *testing.T) {
+    tests := []struct {
+        name         string
+        scores       []int
+        wantScore    int
+        wantDecision Decision
+        wantResults  int
+    }{
+        {"no checks", nil, 0, Accept, 0},
+        {"clean", []int{0, 0}, 0, Accept, 0},
+        {"below flag", []int{29}, 29, Accept, 1},
+        {"at flag", []int{30}, 30, Flag, 1},
+        {"summed to flag", []int{20, 0, 20}, 40, Flag, 2},
+        {"below reject", []int{99}, 99, Flag, 1},
+        {"at reject", []int{100}, 100, Reject, 1},
+        {"summed to reject", []int{50, 50}, 100, Reject, 2},
+        {"negative ignored", []int{-10, 30}, 30, Flag, 1},
+    }
+
+    for _, tt := range tests {
+        checks := make([]Check, 0, len(tt.scores))
+        for i, score := range tt.scores {
+            checks = append(checks, fixed{name: string(rune('a' + i)), score: score})
+        }
+
+        verdict, err := NewPipeline(30, 100, checks...).Screen(context.Background(), Submission{})
+        if err != nil {
+            t.Errorf("%s: Screen: %v", tt.name, err)
+            continue
+        }
+
+        if verdict.Score != tt.wantScore || verdict.Decision != tt.wantDecision || len(verdict.Results) != tt.wantResults {
+            t.Errorf("%s: got score %d, %s, %d results, want score %d, %s, %d results",
+                tt.name, verdict.Score, verdict.Decision, len(verdict.Results),
+                tt.wantScore, tt.wantDecision, tt.wantResults)
+        }
+    }
+}
+
+func TestPipelineScreenError(t *testing.T) {
+    errLookup := errors.New("lookup failed")
+
+    _, err := NewPipeline(30, 100,
+        fixed{name: "first", score: 10},
+        fixed{name: "broken", err: errLookup},
+    ).Screen(context.Background(), Submission{})
+
+    if !errors.Is(err, errLookup) {
+        t.Errorf("Screen error = %v, want %v", err, errLookup)
+    }
+}
+
+func TestLinks(t *testing.T) {
+    check := Links{Allowed: 1, PerLink: 20, NameLink: 50}
+
+    tests := []struct {
+        name      string
+        sub       Submission
+        wantScore int
+    }{
+        {"no links", Submission{Content: "We played mas in Port of Spain."}, 0},
+        {"one allowed", Submission{Content: "Photos at https://example.com/carnival"}, 0},
+        {"www link", Submission{Content: "see www.example.com and www.example.org"}, 20},
+        {"three links", Submission{Content: "http://a.example http://b.example https://c.example"}, 40},
+        {"link in name", Submission{AuthorName: "Keisha https://example.com", Content: "Lovely day."}, 50},
+        {"link in name and content", Submission{AuthorName: "www.example.com", Content: "http://a.example http://b.example https://c.example"}, 90},
+        {"name link with allowed content link", Submission{AuthorName: "www.example.com", Content: "http://a.example"}, 50},
+    }
+
+    for _, tt := range tests {
+        score, reason, err := check.Score(context.Background(), tt.sub)
+        if err != nil {
+            t.Errorf("%s: Score: %v", tt.name, err)
+            continue
+        }
+        if score != tt.wantScore {
+            t.Errorf("%s: score = %d, want %d", tt.name, score, tt.wantScore)
+        }
+        if (score > 0) != (reason != "") {
+            t.Errorf("%s: score %d with reason %q", tt.name, score, reason)
+        }
+    }
+}
+
+func TestBannedWords(t *testing.T) {
+    check := BannedWords{Words: []string{"casino", "seo", "Crypto"}, PerWord: 40}
+
+    tests := []struct {
+        name      string
+        sub       Submission
+        wantScore int
+    }{
+        {"clean", Submission{Content: "Jouvert morning in Port of Spain."}, 0},
+        {"whole word", Submission{Content: "Best casino in town"}, 40},
+        {"ignores case", Submission{Content: "CASINO bonus"}, 40},
+        {"punctuation", Submission{Content: "casino! seo?"}, 80},
+        {"repeated word counts once", Submission{Content: "casino casino Casino"}, 40},
+        {"inside a word", Submission{Content: "We flew to Seoul, then went to the casinos"}, 0},
+        {"prefix of a word", Submission{Content: "cryptography lecture"}, 0},
+        {"banned word list is case insensitive", Submission{Content: "crypto tips"}, 40},
+        {"author name", Submission{AuthorName: "SEO Expert", Content: "Lovely day."}, 40},
+    }
+
+    for _, tt := range tests {
+        score, _, err := check.Score(context.Background(), tt.sub)
+        if err != nil {
+            t.Errorf("%s: Score: %v", tt.name, err)
+            continue
+        }
+        if score != tt.wantScore {
+            t.Errorf("%s: score = %d, want %d", tt.name, score, tt.wantScore)
+        }
+    }
+}
+
+func TestContentHash(t *testing.T) {
+    base := ContentHash("Jouvert morning in Port of Spain")
+
+    same := []string{
+        "jouvert morning in port of spain",
+        "JOUVERT MORNING IN PORT OF SPAIN",
+        "  Jouvert   morning\nin Port\tof Spain  ",
+    }
+    for _, content := range same {
+        if got := ContentHash(content); got != base {
+            t.Errorf("ContentHash(%q) differs from the original", content)
+        }
+    }
+
+    different := []string{
+        "Jouvert morning in San Fernando",
+        "Jouvert morning in Port of Spain!",
+        "JouvertmorninginPortofSpain",
+    }
+    for _, content := range different {
+        if got := ContentHash(content); got == base {
+            t.Errorf("ContentHash(%q) matches the original", content)
+        }
+    }
+}
+
+func TestLength(t *testing.T) {
+    check := Length{Max: 10}
+
+    tests := []struct {
+        name      string
+        content   string
+        wantScore int
+    }{
+        {"empty", "", 0},
+        {"at max", strings.Repeat("a", 10), 0},
+        {"multibyte at max", strings.Repeat("é", 10), 0},
+        {"over max", strings.Repeat("a", 11), RejectScore},
+        {"multibyte over max", strings.Repeat("é", 11), RejectScore},
+    }
+
+    for _, tt := range tests {
+        score, _, err := check.Score(context.Background(), Submission{Content: tt.content})
+        if err != nil {
+            t.Errorf("%s: Score: %v", tt.name, err)
+            continue
+        }
+        if score != tt.wantScore {
+            t.Errorf("%s: score = %d, want %d", tt.name, score, tt.wantScore)
+        }
+    }
+}
+
+func TestHoneypot(t *testing.T) {
+    tests := []struct {
+        honeypot  string
+        wantScore int
+    }{
+        {"", 0},
+        {"https://example.com", RejectScore},
+    }
+
+    for _, tt := range tests {
+        score, _, err := Honeypot{}.Score(context.Background(), Submission{Honeypot: tt.honeypot})
+        if err != nil {
+            t.Errorf("Score(%q): %v", tt.honeypot, err)
+            continue
+        }
+        if score != tt.wantScore {
+            t.Errorf("Score(%q) = %d, want %d", tt.honeypot, score, tt.wantScore)
+        }
+    }
+}
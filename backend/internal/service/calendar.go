@@ -0,0 +1,253 @@
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "net/url"
+    "time"
+
+    "github.com/aidantrabs/kultur/backend/internal/db"
+    "github.com/aidantrabs/kultur/backend/internal/ical"
+    "github.com/google/uuid"
+    "github.com/jackc/pgx/v5"
+    "github.com/jackc/pgx/v5/pgtype"
+)
+
+// alarms go off in the morning rather than at midnight
+const reminderAlarmHour = 9
+
+// CalendarService builds iCalendar feeds of festival dates.
+type CalendarService struct {
+    queries   *db.Queries
+    festivals *FestivalService
+    baseURL   string
+    uidHost   string
+}
+
+func NewCalendarService(queries *db.Queries, festivalService *FestivalService, baseURL string) *CalendarService {
+    host := "kultur-tt.app"
+    if u, err := url.Parse(baseURL); err == nil && u.Hostname() != "" {
+        host = u.Hostname()
+    }
+
+    return &CalendarService{
+        queries:   queries,
+        festivals: festivalService,
+        baseURL:   baseURL,
+        uidHost:   host,
+    }
+}
+
+type CalendarFeedParams struct {
+    Region   string
+    Heritage string
+}
+
+// Feed returns every published festival date from the start of last year
+// on, optionally filtered by region or heritage.
+func (s *CalendarService) Feed(ctx context.Context, params CalendarFeedParams) (ical.Calendar, error) {
+    from := time.Date(time.Now().Year()-1, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+    dates, err := s.queries.ListFestivalDatesForFeed(ctx, db.ListFestivalDatesForFeedParams{
+        FromDate: pgtype.Date{Time: from, Valid: true},
+        Region:   pgtype.Text{String: params.Region, Valid: params.Region != ""},
+        Heritage: pgtype.Text{String: params.Heritage, Valid: params.Heritage != ""},
+    })
+    if err != nil {
+        return ical.Calendar{}, err
+    }
+
+    name := "KULTUR Festivals"
+    switch {
+    case params.Region != "":
+        name = fmt.Sprintf("KULTUR Festivals: %s", label(regionLabels, params.Region))
+    case params.Heritage != "":
+        name = fmt.Sprintf("KULTUR Festivals: %s", label(heritageLabels, params.Heritage))
+    }
+
+    cal := ical.Calendar{
+        Name:        name,
+        Description: "Cultural festivals of Trinidad and Tobago",
+        Events:      make([]ical.Event, 0, len(dates)),
+    }
+
+    for _, d := range dates {
+        cal.Events = append(cal.Events, s.event(festivalEvent{
+            FestivalID:  d.FestivalID,
+            Year:        d.Year,
+            Slug:        d.Slug,
+            Name:        d.Name,
+            Summary:     d.Summary,
+            Region:      d.Region,
+            StartDate:   d.StartDate,
+            EndDate:     d.EndDate,
+            IsTentative: d.IsTentative.Bool,
+        }))
+    }
+
+    return cal, nil
+}
+
+// FestivalFeed returns every date of one published festival.
+func (s *CalendarService) FestivalFeed(ctx context.Context, slug string) (ical.Calendar, error) {
+    festival, err := s.festivals.GetBySlug(ctx, slug)
+    if err != nil {
+        return ical.Calendar{}, err
+    }
+
+    dates, err := s.festivals.GetDates(ctx, festival.ID)
+    if err != nil {
+        return ical.Calendar{}, err
+    }
+
+    cal := ical.Calendar{
+        Name:        festival.Name,
+        Description: festival.Summary,
+        Events:      make([]ical.Event, 0, len(dates)),
+    }
+
+    for _, d := range dates {
+        cal.Events = append(cal.Events, s.event(festivalEvent{
+            FestivalID:  festival.ID,
+            Year:        d.Year,
+            Slug:        festival.Slug,
+            Name:        festival.Name,
+            Summary:     festival.Summary,
+            Region:      festival.Region,
+            StartDate:   d.StartDate,
+            EndDate:     d.EndDate,
+            IsTentative: d.IsTentative.Bool,
+        }))
+    }
+
+    return cal, nil
+}
+
+// PersonalURL is the subscriber's private feed URL for calendarToken.
+func (s *CalendarService) PersonalURL(calendarToken string) string {
+    return fmt.Sprintf("%s/api/calendar/%s.ics", s.baseURL, calendarToken)
+}
+
+// PersonalFeed returns the festivals a confirmed subscriber picked in
+// festival_reminders, with an alarm for each of their lead times.
+func (s *CalendarService) PersonalFeed(ctx context.Context, calendarToken string) (ical.Calendar, error) {
+    sub, err := s.queries.GetSubscriptionByCalendarToken(ctx, calendarToken)
+    if errors.Is(err, pgx.ErrNoRows) {
+        return ical.Calendar{}, ErrInvalidToken
+    }
+    if err != nil {
+        return ical.Calendar{}, err
+    }
+
+    reminders, err := parseReminders(sub.FestivalReminders)
+    if err != nil {
+        return ical.Calendar{}, err
+    }
+
+    leadTimes := make(map[string][]int, len(reminders))
+    for _, r := range reminders {
+        leadTimes[r.Slug] = r.DaysBefore
+    }
+
+    cal := ical.Calendar{
+        Name:        "My KULTUR Festivals",
+        Description: "Festivals you follow on KULTUR",
+        Events:      []ical.Event{},
+    }
+
+    if len(reminders) == 0 {
+        return cal, nil
+    }
+
+    from := time.Date(time.Now().Year()-1, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+    dates, err := s.queries.ListFestivalDatesForFeed(ctx, db.ListFestivalDatesForFeedParams{
+        FromDate: pgtype.Date{Time: from, Valid: true},
+    })
+    if err != nil {
+        return ical.Calendar{}, err
+    }
+
+    for _, d := range dates {
+        days, ok := leadTimes[d.Slug]
+        if !ok {
+            continue
+        }
+
+        event := s.event(festivalEvent{
+            FestivalID:  d.FestivalID,
+            Year:        d.Year,
+            Slug:        d.Slug,
+            Name:        d.Name,
+            Summary:     d.Summary,
+            Region:      d.Region,
+            StartDate:   d.StartDate,
+            EndDate:     d.EndDate,
+            IsTentative: d.IsTentative.Bool,
+        })
+
+        for _, day := range days {
+            event.Alarms = append(event.Alarms, ical.Alarm{
+                DaysBefore:  day,
+                Hour:        reminderAlarmHour,
+                Description: alarmText(d.Name, day),
+            })
+        }
+
+        cal.Events = append(cal.Events, event)
+    }
+
+    return cal, nil
+}
+
+func alarmText(name string, daysBefore int) string {
+    switch daysBefore {
+    case 0:
+        return fmt.Sprintf("%s is today", name)
+    case 1:
+        return fmt.Sprintf("%s is tomorrow", name)
+    default:
+        return fmt.Sprintf("%s is in %d days", name, daysBefore)
+    }
+}
+
+// festivalEvent is the part of a festival date a calendar event is built
+// from, shared by the different date queries.
+type festivalEvent struct {
+    FestivalID  pgtype.UUID
+    Year        int32
+    Slug        string
+    Name        string
+    Summary     string
+    Region      string
+    StartDate   pgtype.Date
+    EndDate     pgtype.Date
+    IsTentative bool
+}
+
+func (s *CalendarService) event(f festivalEvent) ical.Event {
+    festivalURL := fmt.Sprintf("%s/festivals/%s", s.baseURL, f.Slug)
+
+    end := f.StartDate.Time
+    if f.EndDate.Valid {
+        end = f.EndDate.Time
+    }
+
+    status := ical.StatusConfirmed
+    if f.IsTentative {
+        status = ical.StatusTentative
+    }
+
+    return ical.Event{
+        // one event per festival per year, so the UID survives date changes
+        UID:         fmt.Sprintf("%s-%d@%s", uuid.UUID(f.FestivalID.Bytes), f.Year, s.uidHost),
+        Summary:     f.Name,
+        Description: fmt.Sprintf("%s\n\n%s", f.Summary, festivalURL),
+        Location:    label(regionLabels, f.Region),
+        URL:         festivalURL,
+        Start:       f.StartDate.Time,
+        End:         end,
+        Status:      status,
+    }
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+
+    "github.com/aidantrabs/kultur/backend/internal/db"
+    "github.com/aidantrabs/kultur/backend/internal/email"
+    "github.com/aidantrabs/kultur/backend/internal/scheduler"
+    "github.com/google/uuid"
+    "github.com/jackc/pgx/v5"
+    "github.com/jackc/pgx/v5/pgtype"
+)
+
+var heritageLabels = map[string]string{
+    "african":    "African Heritage",
+    "indian":     "Indian Heritage",
+    "indigenous": "Indigenous/First Peoples",
+    "mixed":      "Mixed Heritage",
+    "christian":  "Christian",
+}
+
+var regionLabels = map[string]string{
+    "north":      "North Trinidad",
+    "south":      "South Trinidad",
+    "central":    "Central Trinidad",
+    "east":       "East Trinidad",
+    "west":       "West Trinidad",
+    "tobago":     "Tobago",
+    "nationwide": "Nationwide",
+}
+
+type DigestService struct {
+    queries *db.Queries
+    email   *email.Service
+}
+
+func NewDigestService(queries *db.Queries, emailSvc *email.Service) *DigestService {
+    return &DigestService{
+        queries: queries,
+        email:   emailSvc,
+    }
+}
+
+// SendWeekly sends the upcoming festivals digest to every confirmed weekly
+// digest subscriber. Each send is recorded in digest_deliveries under the
+// scheduler period first, so a retried run only reaches the subscribers the
+// failed run missed.
+func (s *DigestService) SendWeekly(ctx context.Context) error {
+    period := scheduler.Period(ctx)
+    if period == "" {
+        return errors.New("weekly digest must run from the scheduler")
+    }
+
+    subs, err := s.queries.ListConfirmedWeeklyDigest(ctx)
+    if err != nil {
+        return fmt.Errorf("failed to list digest subscribers: %w", err)
+    }
+
+    if len(subs) == 0 {
+        return nil
+    }
+
+    upcoming, err := s.queries.ListUpcomingFestivalDates(ctx)
+    if err != nil {
+        return fmt.Errorf("failed to list upcoming festivals: %w", err)
+    }
+
+    items := digestItems(upcoming)
+
+    var failed int
+    for _, sub := range subs {
+        if err := s.send(ctx, sub, items, period); err != nil {
+            log.Printf("digest: failed to send to subscription %s: %v", uuid.UUID(sub.ID.Bytes), err)
+            failed++
+        }
+    }
+
+    if failed > 0 {
+        return fmt.Errorf("failed to send %d of %d digests", failed, len(subs))
+    }
+
+    return nil
+}
+
+func (s *DigestService) send(ctx context.Context, sub db.Subscription, items []email.FestivalDigestItem, period string) error {
+    delivery, err := s.queries.CreateDigestDelivery(ctx, db.CreateDigestDeliveryParams{
+        SubscriptionID: sub.ID,
+        PeriodKey:      period,
+    })
+    if errors.Is(err, pgx.ErrNoRows) {
+        return nil // already sent
+    }
+    if err != nil {
+        return err
+    }
+
+    if err := s.email.SendWeeklyDigest(ctx, sub.Email, items, sub.UnsubscribeToken); err != nil {
+        // release the delivery so the retried run can send it
+        if delErr := s.queries.DeleteDigestDelivery(ctx, delivery.ID); delErr != nil {
+            log.Printf("digest: failed to release delivery %s: %v", uuid.UUID(delivery.ID.Bytes), delErr)
+        }
+        return err
+    }
+
+    return nil
+}
+
+func digestItems(upcoming []db.ListUpcomingFestivalDatesRow) []email.FestivalDigestItem {
+    items := make([]email.FestivalDigestItem, 0, len(upcoming))
+    for _, f := range upcoming {
+        items = append(items, email.FestivalDigestItem{
+            Name:     f.Name,
+            Slug:     f.Slug,
+            Date:     formatDateRange(f.StartDate, f.EndDate),
+            Heritage: label(heritageLabels, f.HeritageType),
+            Region:   label(regionLabels, f.Region),
+        })
+    }
+
+    return items
+}
+
+func label(labels map[string]string, key string) string {
+    if l, ok := labels[key]; ok {
+        return l
+    }
+
+    return key
+}
+
+// formatDateRange renders dates the way the emails show them, e.g.
+// "February 16-17, 2026" or "February 28 - March 2, 2026".
+func formatDateRange(start, end pgtype.Date) string {
+    if !end.Valid || end.Time.Equal(start.Time) {
+        return start.Time.Format("January 2, 2006")
+    }
+
+    s, e := start.Time, end.Time
+
+    switch {
+    case s.Year() != e.Year():
+        return fmt.Sprintf("%s - %s", s.Format("January 2, 2006"), e.Format("January 2, 2006"))
+    case s.Month() != e.Month():
+        return fmt.Sprintf("%s - %s", s.Format("January 2"), e.Format("January 2, 2006"))
+    default:
+        return fmt.Sprintf("%s-%d, %d", s.Format("January 2"), e.Day(), e.Year())
+    }
+}
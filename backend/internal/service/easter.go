@@ -0,0 +1,90 @@
+package service
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "log"
+
+    "github.com/aidantrabs/kultur/backend/internal/calendar"
+    "github.com/aidantrabs/kultur/backend/internal/db"
+    "github.com/google/uuid"
+    "github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrInvalidYearRange = errors.New("invalid year range")
+
+// MaxPrefillYears caps how many years one pre-fill request may cover.
+const MaxPrefillYears = 50
+
+// EasterDate is one Easter-relative observance in a given year.
+type EasterDate struct {
+    Name       string `json:"name"`
+    OffsetDays int    `json:"offsetDays"`
+    Date       string `json:"date"`
+}
+
+// EasterDates returns Easter Sunday and the observances that move with it
+// for year.
+func (s *FestivalService) EasterDates(year int) []EasterDate {
+    easter := calendar.Easter(year)
+
+    dates := make([]EasterDate, 0, len(calendar.EasterOffsets))
+    for _, o := range calendar.EasterOffsets {
+        dates = append(dates, EasterDate{
+            Name:       o.Name,
+            OffsetDays: o.OffsetDays,
+            Date:       easter.AddDate(0, 0, o.OffsetDays).Format("2006-01-02"),
+        })
+    }
+
+    return dates
+}
+
+// PrefillEasterDates writes confirmed festival_dates for every festival with
+// an Easter-relative rule, for fromYear through toYear. Computed dates are
+// exact, so they're stored with is_tentative=false. Admin overrides are kept.
+func (s *FestivalService) PrefillEasterDates(ctx context.Context, fromYear, toYear int) (int64, error) {
+    if fromYear < 1583 || toYear < fromYear || toYear-fromYear >= MaxPrefillYears {
+        return 0, fmt.Errorf("%w: years must be from 1583 on, at most %d at a time", ErrInvalidYearRange, MaxPrefillYears)
+    }
+
+    festivals, err := s.queries.ListFestivalsWithRecurrence(ctx)
+    if err != nil {
+        return 0, err
+    }
+
+    var written int64
+    for _, f := range festivals {
+        var rule calendar.Rule
+        if err := json.Unmarshal(f.Recurrence, &rule); err != nil {
+            log.Printf("recurrence: festival %s: invalid rule: %v", uuid.UUID(f.ID.Bytes), err)
+            continue
+        }
+
+        if rule.Type != calendar.RuleEaster {
+            continue
+        }
+
+        for y := fromYear; y <= toYear; y++ {
+            start, end, err := rule.Dates(y)
+            if err != nil {
+                return written, err
+            }
+
+            n, err := s.queries.UpsertComputedFestivalDate(ctx, db.UpsertComputedFestivalDateParams{
+                FestivalID: f.ID,
+                Year:       int32(y),
+                StartDate:  pgtype.Date{Time: start, Valid: true},
+                EndDate:    pgtype.Date{Time: end, Valid: !end.Equal(start)},
+            })
+            if err != nil {
+                return written, err
+            }
+            written += n
+        }
+    }
+
+    return written, nil
+}
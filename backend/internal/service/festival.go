@@ -3,10 +3,14 @@ package service
 import (
     "context"
     "errors"
+    "fmt"
+    "time"
 
     "github.com/aidantrabs/kultur/backend/internal/db"
+    "github.com/aidantrabs/kultur/backend/internal/repository"
     "github.com/jackc/pgx/v5"
     "github.com/jackc/pgx/v5/pgtype"
+    "github.com/jackc/pgx/v5/pgxpool"
 )
 
 var (
@@ -15,27 +19,102 @@ var (
 )
 
 type FestivalService struct {
-    queries *db.Queries
+    pool      *pgxpool.Pool
+    queries   *db.Queries
+    festivals *repository.FestivalRepository
 }
 
-func NewFestivalService(queries *db.Queries) *FestivalService {
-    return &FestivalService{queries: queries}
+func NewFestivalService(pool *pgxpool.Pool, queries *db.Queries) *FestivalService {
+    return &FestivalService{
+        pool:      pool,
+        queries:   queries,
+        festivals: repository.NewFestivalRepository(pool),
+    }
 }
 
 type ListFestivalsParams struct {
-    Region   string
-    Heritage string
-}
+    Region       string
+    HeritageType string
+    FestivalType string
+    DateType     string
+    Month        int
+    HasUpcoming  *bool
+    From         *time.Time
+    To           *time.Time
+    Sort         string
+    Cursor       string
+    Limit        int
+}
+
+type festivalCursor struct {
+    Sort string `json:"sort"`
+    repository.FestivalCursor
+}
+
+// List returns one page of published festivals matching every filter that is
+// set. The cursor only continues a listing with the same sort.
+func (s *FestivalService) List(ctx context.Context, params ListFestivalsParams) (Page[repository.ListPublishedFestivalsRow], error) {
+    sort := params.Sort
+    if sort == "" {
+        sort = repository.FestivalSortName
+    }
 
-func (s *FestivalService) List(ctx context.Context, params ListFestivalsParams) ([]db.Festival, error) {
-    switch {
-    case params.Region != "":
-        return s.queries.ListFestivalsByRegion(ctx, params.Region)
-    case params.Heritage != "":
-        return s.queries.ListFestivalsByHeritage(ctx, params.Heritage)
+    switch sort {
+    case repository.FestivalSortName, repository.FestivalSortNextDate, repository.FestivalSortCreatedAt:
     default:
-        return s.queries.ListFestivals(ctx)
+        return Page[repository.ListPublishedFestivalsRow]{}, fmt.Errorf("%w: sort must be name, next_date or created_at", ErrInvalidFilter)
+    }
+
+    if params.Month < 0 || params.Month > 12 {
+        return Page[repository.ListPublishedFestivalsRow]{}, fmt.Errorf("%w: month must be between 1 and 12", ErrInvalidFilter)
+    }
+
+    from, to, err := dateRange(params.From, params.To)
+    if err != nil {
+        return Page[repository.ListPublishedFestivalsRow]{}, err
+    }
+
+    size := pageSize(params.Limit)
+    arg := repository.ListPublishedFestivalsParams{
+        Region:       params.Region,
+        HeritageType: params.HeritageType,
+        FestivalType: params.FestivalType,
+        DateType:     params.DateType,
+        Month:        params.Month,
+        HasUpcoming:  optionalBool(params.HasUpcoming),
+        From:         from,
+        To:           to,
+        Sort:         sort,
+        Limit:        int32(size + 1),
+    }
+
+    if params.Cursor != "" {
+        var cursor festivalCursor
+        if err := decodeCursor(params.Cursor, &cursor); err != nil {
+            return Page[repository.ListPublishedFestivalsRow]{}, err
+        }
+        if cursor.Sort != sort || !cursor.ID.Valid {
+            return Page[repository.ListPublishedFestivalsRow]{}, ErrInvalidCursor
+        }
+        arg.After = &cursor.FestivalCursor
+    }
+
+    rows, err := s.festivals.ListPublishedFestivals(ctx, arg)
+    if err != nil {
+        return Page[repository.ListPublishedFestivalsRow]{}, err
     }
+
+    return newPage(rows, size, func(last repository.ListPublishedFestivalsRow) (string, error) {
+        return encodeCursor(festivalCursor{
+            Sort: sort,
+            FestivalCursor: repository.FestivalCursor{
+                ID:            last.ID,
+                Name:          last.Name,
+                NextStartDate: last.NextStartDate,
+                CreatedAt:     last.CreatedAt,
+            },
+        })
+    })
 }
 
 func (s *FestivalService) ListUpcoming(ctx context.Context) ([]db.ListUpcomingFestivalDatesRow, error) {
@@ -64,19 +143,6 @@ func (s *FestivalService) GetByID(ctx context.Context, id pgtype.UUID) (db.Festi
     return festival, err
 }
 
-func (s *FestivalService) Create(ctx context.Context, params db.CreateFestivalParams) (db.Festival, error) {
-    return s.queries.CreateFestival(ctx, params)
-}
-
-func (s *FestivalService) Update(ctx context.Context, params db.UpdateFestivalParams) (db.Festival, error) {
-    festival, err := s.queries.UpdateFestival(ctx, params)
-    if errors.Is(err, pgx.ErrNoRows) {
-        return db.Festival{}, ErrFestivalNotFound
-    }
-
-    return festival, err
-}
-
 func (s *FestivalService) Delete(ctx context.Context, id pgtype.UUID) error {
     return s.queries.DeleteFestival(ctx, id)
 }
@@ -0,0 +1,317 @@
+package service
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "net/url"
+    "regexp"
+    "slices"
+    "strings"
+    "unicode/utf8"
+
+    "github.com/aidantrabs/kultur/backend/internal/db"
+    "github.com/jackc/pgx/v5"
+    "github.com/jackc/pgx/v5/pgconn"
+    "github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+    ErrInvalidFestival = errors.New("invalid festival")
+    ErrInvalidPatch    = errors.New("invalid patch")
+    ErrSlugTaken       = errors.New("slug already in use")
+    // ErrFestivalChanged means the festival was updated after the version
+    // the caller's If-Match names.
+    ErrFestivalChanged = errors.New("festival has changed")
+)
+
+var (
+    slugPattern   = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+    dateTypes     = []string{"fixed", "lunar", "movable"}
+    festivalTypes = []string{"religious", "cultural", "national", "community"}
+)
+
+// uniqueViolation is the Postgres error code for a unique constraint.
+const uniqueViolation = "23505"
+
+// FestivalFields are the fields admins edit directly, named as in the API.
+// Nil optional fields are stored as NULL. Media and recurrence have their
+// own endpoints.
+type FestivalFields struct {
+    Slug             string  `json:"slug"`
+    Name             string  `json:"name"`
+    DateType         string  `json:"date_type"`
+    Region           string  `json:"region"`
+    HeritageType     string  `json:"heritage_type"`
+    FestivalType     string  `json:"festival_type"`
+    Summary          string  `json:"summary"`
+    Story            *string `json:"story"`
+    WhatToExpect     *string `json:"what_to_expect"`
+    HowToParticipate *string `json:"how_to_participate"`
+    PracticalInfo    *string `json:"practical_info"`
+    CoverImageURL    *string `json:"cover_image_url"`
+    IsPublished      bool    `json:"is_published"`
+}
+
+// requiredFestivalFields can't be removed by a patch.
+var requiredFestivalFields = []string{"slug", "name", "date_type", "region", "heritage_type", "festival_type", "summary", "is_published"}
+
+func (f FestivalFields) validate() error {
+    switch {
+    case f.Slug == "" || f.Name == "":
+        return fmt.Errorf("%w: slug and name are required", ErrInvalidFestival)
+    case len(f.Slug) > 100 || !slugPattern.MatchString(f.Slug):
+        return fmt.Errorf("%w: slug must be lowercase letters, digits and dashes, at most 100 characters", ErrInvalidFestival)
+    case utf8.RuneCountInString(f.Name) > 200:
+        return fmt.Errorf("%w: name is longer than 200 characters", ErrInvalidFestival)
+    case !slices.Contains(dateTypes, f.DateType):
+        return fmt.Errorf("%w: date_type must be one of %s", ErrInvalidFestival, strings.Join(dateTypes, ", "))
+    case regionLabels[f.Region] == "":
+        return fmt.Errorf("%w: unknown region %q", ErrInvalidFestival, f.Region)
+    case heritageLabels[f.HeritageType] == "":
+        return fmt.Errorf("%w: unknown heritage_type %q", ErrInvalidFestival, f.HeritageType)
+    case !slices.Contains(festivalTypes, f.FestivalType):
+        return fmt.Errorf("%w: festival_type must be one of %s", ErrInvalidFestival, strings.Join(festivalTypes, ", "))
+    case strings.TrimSpace(f.Summary) == "":
+        return fmt.Errorf("%w: summary is required", ErrInvalidFestival)
+    }
+
+    if f.CoverImageURL != nil {
+        u, err := url.Parse(*f.CoverImageURL)
+        if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+            return fmt.Errorf("%w: cover_image_url must be an absolute http(s) URL", ErrInvalidFestival)
+        }
+    }
+
+    return nil
+}
+
+// normalize stores empty optional fields as NULL, so clearing one with ""
+// gives the same row whether it came through PUT or PATCH.
+func (f *FestivalFields) normalize() {
+    for _, field := range []**string{&f.Story, &f.WhatToExpect, &f.HowToParticipate, &f.PracticalInfo, &f.CoverImageURL} {
+        if *field != nil && **field == "" {
+            *field = nil
+        }
+    }
+}
+
+func festivalFields(f db.Festival) FestivalFields {
+    optional := func(t pgtype.Text) *string {
+        if !t.Valid {
+            return nil
+        }
+        return &t.String
+    }
+
+    return FestivalFields{
+        Slug:             f.Slug,
+        Name:             f.Name,
+        DateType:         f.DateType,
+        Region:           f.Region,
+        HeritageType:     f.HeritageType,
+        FestivalType:     f.FestivalType,
+        Summary:          f.Summary,
+        Story:            optional(f.Story),
+        WhatToExpect:     optional(f.WhatToExpect),
+        HowToParticipate: optional(f.HowToParticipate),
+        PracticalInfo:    optional(f.PracticalInfo),
+        CoverImageURL:    optional(f.CoverImageUrl),
+        IsPublished:      f.IsPublished.Bool,
+    }
+}
+
+func optionalText(s *string) pgtype.Text {
+    if s == nil {
+        return pgtype.Text{}
+    }
+
+    return pgtype.Text{String: *s, Valid: true}
+}
+
+// FestivalETag identifies the version of a festival, changing on every
+// update. Updates set updated_at from clock_timestamp(), the time the
+// statement runs, rather than NOW(), the transaction's start. Edits hold the
+// row lock, so an edit that waited on another one stamps a later time.
+func FestivalETag(f db.Festival) string {
+    return fmt.Sprintf(`"%x"`, f.UpdatedAt.Time.UnixMicro())
+}
+
+// etagMatches reports whether an If-Match header names the festival's
+// current version. Weak tags never match.
+func etagMatches(ifMatch string, f db.Festival) bool {
+    current := FestivalETag(f)
+    for _, tag := range strings.Split(ifMatch, ",") {
+        tag = strings.TrimSpace(tag)
+        if tag == "*" || tag == current {
+            return true
+        }
+    }
+
+    return false
+}
+
+func (s *FestivalService) Create(ctx context.Context, fields FestivalFields) (db.Festival, error) {
+    fields.normalize()
+    if err := fields.validate(); err != nil {
+        return db.Festival{}, err
+    }
+
+    festival, err := s.queries.CreateFestival(ctx, db.CreateFestivalParams{
+        Slug:             fields.Slug,
+        Name:             fields.Name,
+        DateType:         fields.DateType,
+        Region:           fields.Region,
+        HeritageType:     fields.HeritageType,
+        FestivalType:     fields.FestivalType,
+        Summary:          fields.Summary,
+        Story:            optionalText(fields.Story),
+        WhatToExpect:     optionalText(fields.WhatToExpect),
+        HowToParticipate: optionalText(fields.HowToParticipate),
+        PracticalInfo:    optionalText(fields.PracticalInfo),
+        CoverImageUrl:    optionalText(fields.CoverImageURL),
+        GalleryImages:    []byte("[]"),
+        VideoEmbeds:      []byte("[]"),
+        IsPublished:      pgtype.Bool{Bool: fields.IsPublished, Valid: true},
+    })
+
+    return festival, slugError(err)
+}
+
+// Update replaces every editable field. When ifMatch is set the update only
+// goes ahead if it names the festival's current version.
+func (s *FestivalService) Update(ctx context.Context, id pgtype.UUID, fields FestivalFields, ifMatch string) (db.Festival, error) {
+    fields.normalize()
+    if err := fields.validate(); err != nil {
+        return db.Festival{}, err
+    }
+
+    return s.edit(ctx, id, ifMatch, func(db.Festival) (FestivalFields, error) {
+        return fields, nil
+    })
+}
+
+// Patch applies a JSON Merge Patch (RFC 7396) to the festival's editable
+// fields: members set to null are cleared, absent members are left as they
+// are. ifMatch must name the festival's current version.
+func (s *FestivalService) Patch(ctx context.Context, id pgtype.UUID, patch []byte, ifMatch string) (db.Festival, error) {
+    var changes map[string]any
+    if err := json.Unmarshal(patch, &changes); err != nil || changes == nil {
+        return db.Festival{}, fmt.Errorf("%w: body must be a JSON object", ErrInvalidPatch)
+    }
+
+    return s.edit(ctx, id, ifMatch, func(current db.Festival) (FestivalFields, error) {
+        doc, err := json.Marshal(festivalFields(current))
+        if err != nil {
+            return FestivalFields{}, err
+        }
+
+        var target map[string]any
+        if err := json.Unmarshal(doc, &target); err != nil {
+            return FestivalFields{}, err
+        }
+
+        merged := mergePatch(target, changes).(map[string]any)
+        for _, name := range requiredFestivalFields {
+            if _, ok := merged[name]; !ok {
+                return FestivalFields{}, fmt.Errorf("%w: %s cannot be null", ErrInvalidFestival, name)
+            }
+        }
+
+        raw, err := json.Marshal(merged)
+        if err != nil {
+            return FestivalFields{}, err
+        }
+
+        var fields FestivalFields
+        dec := json.NewDecoder(bytes.NewReader(raw))
+        dec.DisallowUnknownFields()
+        if err := dec.Decode(&fields); err != nil {
+            return FestivalFields{}, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+        }
+
+        fields.normalize()
+        return fields, fields.validate()
+    })
+}
+
+// edit locks the festival, checks ifMatch against it, and stores the fields
+// fn returns.
+func (s *FestivalService) edit(ctx context.Context, id pgtype.UUID, ifMatch string, fn func(db.Festival) (FestivalFields, error)) (db.Festival, error) {
+    var festival db.Festival
+    err := inTx(ctx, s.pool, s.queries, func(q *db.Queries) error {
+        current, err := q.GetFestivalForUpdate(ctx, id)
+        if errors.Is(err, pgx.ErrNoRows) {
+            return ErrFestivalNotFound
+        }
+        if err != nil {
+            return err
+        }
+
+        if ifMatch != "" && !etagMatches(ifMatch, current) {
+            return ErrFestivalChanged
+        }
+
+        fields, err := fn(current)
+        if err != nil {
+            return err
+        }
+
+        festival, err = q.UpdateFestival(ctx, db.UpdateFestivalParams{
+            ID:               id,
+            Slug:             fields.Slug,
+            Name:             fields.Name,
+            DateType:         fields.DateType,
+            Region:           fields.Region,
+            HeritageType:     fields.HeritageType,
+            FestivalType:     fields.FestivalType,
+            Summary:          fields.Summary,
+            Story:            optionalText(fields.Story),
+            WhatToExpect:     optionalText(fields.WhatToExpect),
+            HowToParticipate: optionalText(fields.HowToParticipate),
+            PracticalInfo:    optionalText(fields.PracticalInfo),
+            CoverImageUrl:    optionalText(fields.CoverImageURL),
+            IsPublished:      pgtype.Bool{Bool: fields.IsPublished, Valid: true},
+        })
+        return slugError(err)
+    })
+    if err != nil {
+        return db.Festival{}, err
+    }
+
+    return festival, nil
+}
+
+func slugError(err error) error {
+    var pgErr *pgconn.PgError
+    if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+        return ErrSlugTaken
+    }
+
+    return err
+}
+
+// mergePatch applies patch to target as RFC 7396 describes.
+func mergePatch(target, patch any) any {
+    changes, ok := patch.(map[string]any)
+    if !ok {
+        return patch
+    }
+
+    doc, ok := target.(map[string]any)
+    if !ok {
+        doc = map[string]any{}
+    }
+
+    for name, value := range changes {
+        if value == nil {
+            delete(doc, name)
+            continue
+        }
+        doc[name] = mergePatch(doc[name], value)
+    }
+
+    return doc
+}
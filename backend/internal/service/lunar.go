@@ -0,0 +1,83 @@
+package service
+
+import (
+    "context"
+    "errors"
+    "time"
+
+    "github.com/aidantrabs/kultur/backend/internal/calendar"
+    "github.com/aidantrabs/kultur/backend/internal/db"
+    "github.com/jackc/pgx/v5"
+    "github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrInvalidDateRange = errors.New("end_date is before start_date")
+
+// LunarEstimate is the estimated date of a lunar festival in a given year.
+type LunarEstimate struct {
+    Name      string        `json:"name"`
+    StartDate string        `json:"startDate"`
+    EndDate   string        `json:"endDate"`
+    Rule      calendar.Rule `json:"rule"`
+}
+
+// LunarEstimates estimates Divali, Phagwa, Eid-ul-Fitr and Hosay for year.
+// Festivals without an estimate for the year are left out.
+func (s *FestivalService) LunarEstimates(year int) []LunarEstimate {
+    estimates := make([]LunarEstimate, 0, len(calendar.LunarFestivals))
+    for _, f := range calendar.LunarFestivals {
+        start, end, err := f.Rule.Dates(year)
+        if err != nil {
+            continue
+        }
+
+        estimates = append(estimates, LunarEstimate{
+            Name:      f.Name,
+            StartDate: start.Format("2006-01-02"),
+            EndDate:   end.Format("2006-01-02"),
+            Rule:      f.Rule,
+        })
+    }
+
+    return estimates
+}
+
+func (s *FestivalService) ListTentativeDates(ctx context.Context) ([]db.ListTentativeFestivalDatesRow, error) {
+    return s.queries.ListTentativeFestivalDates(ctx)
+}
+
+// ConfirmDate marks a festival date as official once it has been announced,
+// optionally correcting it first. Confirmed dates become admin overrides, so
+// they are never regenerated.
+func (s *FestivalService) ConfirmDate(ctx context.Context, id pgtype.UUID, start, end *time.Time) (db.FestivalDate, error) {
+    date, err := s.queries.GetFestivalDateByID(ctx, id)
+    if errors.Is(err, pgx.ErrNoRows) {
+        return db.FestivalDate{}, ErrFestivalDateNotFound
+    }
+    if err != nil {
+        return db.FestivalDate{}, err
+    }
+
+    // a new start date without an end date keeps the festival's length
+    if start != nil {
+        if date.EndDate.Valid && end == nil {
+            length := date.EndDate.Time.Sub(date.StartDate.Time)
+            date.EndDate.Time = start.Add(length)
+        }
+        date.StartDate = pgtype.Date{Time: *start, Valid: true}
+    }
+    if end != nil {
+        date.EndDate = pgtype.Date{Time: *end, Valid: true}
+    }
+
+    if date.EndDate.Valid && date.EndDate.Time.Before(date.StartDate.Time) {
+        return db.FestivalDate{}, ErrInvalidDateRange
+    }
+
+    return s.UpdateDate(ctx, db.UpdateFestivalDateParams{
+        ID:          date.ID,
+        StartDate:   date.StartDate,
+        EndDate:     date.EndDate,
+        IsTentative: pgtype.Bool{Bool: false, Valid: true},
+    })
+}
@@ -0,0 +1,341 @@
+package service
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "net/url"
+    "regexp"
+    "strings"
+    "unicode/utf8"
+
+    "github.com/aidantrabs/kultur/backend/internal/db"
+    "github.com/google/uuid"
+    "github.com/jackc/pgx/v5"
+    "github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+    ErrInvalidMedia  = errors.New("invalid media")
+    ErrMediaNotFound = errors.New("media item not found")
+)
+
+const (
+    MaxGalleryImages = 50
+    MaxVideoEmbeds   = 20
+
+    VideoProviderYouTube = "youtube"
+    VideoProviderVimeo   = "vimeo"
+)
+
+var videoIDPatterns = map[string]*regexp.Regexp{
+    VideoProviderYouTube: regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`),
+    VideoProviderVimeo:   regexp.MustCompile(`^[0-9]{6,12}$`),
+}
+
+// GalleryImage is one entry of the gallery_images column. Order is the
+// image's position in the gallery, starting at 0.
+type GalleryImage struct {
+    ID      string `json:"id"`
+    URL     string `json:"url"`
+    Caption string `json:"caption"`
+    Credit  string `json:"credit"`
+    Alt     string `json:"alt"`
+    Order   int    `json:"order"`
+}
+
+// VideoEmbed is one entry of the video_embeds column. VideoID is the
+// provider's id for the video, not a URL.
+type VideoEmbed struct {
+    ID       string `json:"id"`
+    Provider string `json:"provider"`
+    VideoID  string `json:"videoId"`
+    Title    string `json:"title"`
+    Order    int    `json:"order"`
+}
+
+func (i GalleryImage) itemID() string { return i.ID }
+func (v VideoEmbed) itemID() string   { return v.ID }
+
+type GalleryImageParams struct {
+    URL     string
+    Caption string
+    Credit  string
+    Alt     string
+}
+
+func (p GalleryImageParams) validate() error {
+    u, err := url.Parse(p.URL)
+    if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+        return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidMedia)
+    }
+
+    if strings.TrimSpace(p.Alt) == "" {
+        return fmt.Errorf("%w: alt is required", ErrInvalidMedia)
+    }
+
+    for name, v := range map[string]string{"alt": p.Alt, "caption": p.Caption, "credit": p.Credit} {
+        if utf8.RuneCountInString(v) > 500 {
+            return fmt.Errorf("%w: %s is longer than 500 characters", ErrInvalidMedia, name)
+        }
+    }
+
+    return nil
+}
+
+// VideoEmbedParams names a video either by Provider and VideoID or by URL,
+// a YouTube or Vimeo link the provider and id are read from.
+type VideoEmbedParams struct {
+    Provider string
+    VideoID  string
+    URL      string
+    Title    string
+}
+
+func (p VideoEmbedParams) embed() (VideoEmbed, error) {
+    provider, videoID := strings.ToLower(p.Provider), p.VideoID
+    if p.URL != "" {
+        var ok bool
+        provider, videoID, ok = parseVideoURL(p.URL)
+        if !ok {
+            return VideoEmbed{}, fmt.Errorf("%w: url must be a YouTube or Vimeo video link", ErrInvalidMedia)
+        }
+    }
+
+    pattern, ok := videoIDPatterns[provider]
+    if !ok {
+        return VideoEmbed{}, fmt.Errorf("%w: provider must be youtube or vimeo", ErrInvalidMedia)
+    }
+
+    if !pattern.MatchString(videoID) {
+        return VideoEmbed{}, fmt.Errorf("%w: invalid %s video id", ErrInvalidMedia, provider)
+    }
+
+    title := strings.TrimSpace(p.Title)
+    if title == "" {
+        return VideoEmbed{}, fmt.Errorf("%w: title is required", ErrInvalidMedia)
+    }
+    if utf8.RuneCountInString(title) > 500 {
+        return VideoEmbed{}, fmt.Errorf("%w: title is longer than 500 characters", ErrInvalidMedia)
+    }
+
+    return VideoEmbed{ID: uuid.NewString(), Provider: provider, VideoID: videoID, Title: title}, nil
+}
+
+// parseVideoURL reads the provider and video id from the usual YouTube and
+// Vimeo watch, share and embed links.
+func parseVideoURL(raw string) (provider, videoID string, ok bool) {
+    u, err := url.Parse(raw)
+    if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+        return "", "", false
+    }
+
+    host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+    segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+    switch host {
+    case "youtube.com", "m.youtube.com", "youtube-nocookie.com":
+        if segments[0] == "watch" {
+            return VideoProviderYouTube, u.Query().Get("v"), true
+        }
+        if len(segments) == 2 && (segments[0] == "embed" || segments[0] == "shorts" || segments[0] == "live") {
+            return VideoProviderYouTube, segments[1], true
+        }
+    case "youtu.be":
+        if len(segments) == 1 {
+            return VideoProviderYouTube, segments[0], true
+        }
+    case "vimeo.com":
+        if len(segments) == 1 {
+            return VideoProviderVimeo, segments[0], true
+        }
+    case "player.vimeo.com":
+        if len(segments) == 2 && segments[0] == "video" {
+            return VideoProviderVimeo, segments[1], true
+        }
+    }
+
+    return "", "", false
+}
+
+// AddGalleryImage appends an image to the end of the festival's gallery.
+func (s *FestivalService) AddGalleryImage(ctx context.Context, id pgtype.UUID, params GalleryImageParams) (db.Festival, error) {
+    if err := params.validate(); err != nil {
+        return db.Festival{}, err
+    }
+
+    return s.updateGallery(ctx, id, func(images []GalleryImage) ([]GalleryImage, error) {
+        if len(images) >= MaxGalleryImages {
+            return nil, fmt.Errorf("%w: a gallery holds at most %d images", ErrInvalidMedia, MaxGalleryImages)
+        }
+
+        return append(images, GalleryImage{
+            ID:      uuid.NewString(),
+            URL:     params.URL,
+            Caption: strings.TrimSpace(params.Caption),
+            Credit:  strings.TrimSpace(params.Credit),
+            Alt:     strings.TrimSpace(params.Alt),
+        }), nil
+    })
+}
+
+// ReorderGallery puts the gallery in the order of ids, which must list every
+// image exactly once.
+func (s *FestivalService) ReorderGallery(ctx context.Context, id pgtype.UUID, ids []string) (db.Festival, error) {
+    return s.updateGallery(ctx, id, func(images []GalleryImage) ([]GalleryImage, error) {
+        return reorderMedia(images, ids)
+    })
+}
+
+func (s *FestivalService) RemoveGalleryImage(ctx context.Context, id pgtype.UUID, itemID string) (db.Festival, error) {
+    return s.updateGallery(ctx, id, func(images []GalleryImage) ([]GalleryImage, error) {
+        return removeMedia(images, itemID)
+    })
+}
+
+// AddVideoEmbed appends a video to the end of the festival's videos.
+func (s *FestivalService) AddVideoEmbed(ctx context.Context, id pgtype.UUID, params VideoEmbedParams) (db.Festival, error) {
+    embed, err := params.embed()
+    if err != nil {
+        return db.Festival{}, err
+    }
+
+    return s.updateVideos(ctx, id, func(videos []VideoEmbed) ([]VideoEmbed, error) {
+        if len(videos) >= MaxVideoEmbeds {
+            return nil, fmt.Errorf("%w: a festival has at most %d videos", ErrInvalidMedia, MaxVideoEmbeds)
+        }
+
+        return append(videos, embed), nil
+    })
+}
+
+// ReorderVideos puts the videos in the order of ids, which must list every
+// video exactly once.
+func (s *FestivalService) ReorderVideos(ctx context.Context, id pgtype.UUID, ids []string) (db.Festival, error) {
+    return s.updateVideos(ctx, id, func(videos []VideoEmbed) ([]VideoEmbed, error) {
+        return reorderMedia(videos, ids)
+    })
+}
+
+func (s *FestivalService) RemoveVideoEmbed(ctx context.Context, id pgtype.UUID, itemID string) (db.Festival, error) {
+    return s.updateVideos(ctx, id, func(videos []VideoEmbed) ([]VideoEmbed, error) {
+        return removeMedia(videos, itemID)
+    })
+}
+
+func (s *FestivalService) updateGallery(ctx context.Context, id pgtype.UUID, fn func([]GalleryImage) ([]GalleryImage, error)) (db.Festival, error) {
+    return s.updateMedia(ctx, id, func(f *db.Festival) error {
+        var images []GalleryImage
+        if err := decodeMedia(f.GalleryImages, &images); err != nil {
+            return err
+        }
+
+        images, err := fn(images)
+        if err != nil {
+            return err
+        }
+        for i := range images {
+            images[i].Order = i
+        }
+
+        f.GalleryImages, err = json.Marshal(images)
+        return err
+    })
+}
+
+func (s *FestivalService) updateVideos(ctx context.Context, id pgtype.UUID, fn func([]VideoEmbed) ([]VideoEmbed, error)) (db.Festival, error) {
+    return s.updateMedia(ctx, id, func(f *db.Festival) error {
+        var videos []VideoEmbed
+        if err := decodeMedia(f.VideoEmbeds, &videos); err != nil {
+            return err
+        }
+
+        videos, err := fn(videos)
+        if err != nil {
+            return err
+        }
+        for i := range videos {
+            videos[i].Order = i
+        }
+
+        f.VideoEmbeds, err = json.Marshal(videos)
+        return err
+    })
+}
+
+// updateMedia locks the festival row while fn edits its media, so concurrent
+// edits don't overwrite each other.
+func (s *FestivalService) updateMedia(ctx context.Context, id pgtype.UUID, fn func(f *db.Festival) error) (db.Festival, error) {
+    var festival db.Festival
+    err := inTx(ctx, s.pool, s.queries, func(q *db.Queries) error {
+        f, err := q.GetFestivalForUpdate(ctx, id)
+        if errors.Is(err, pgx.ErrNoRows) {
+            return ErrFestivalNotFound
+        }
+        if err != nil {
+            return err
+        }
+
+        if err := fn(&f); err != nil {
+            return err
+        }
+
+        festival, err = q.UpdateFestivalMedia(ctx, db.UpdateFestivalMediaParams{
+            ID:            id,
+            GalleryImages: f.GalleryImages,
+            VideoEmbeds:   f.VideoEmbeds,
+        })
+        return err
+    })
+    if err != nil {
+        return db.Festival{}, err
+    }
+
+    return festival, nil
+}
+
+func decodeMedia(raw []byte, v any) error {
+    if len(raw) == 0 {
+        return nil
+    }
+
+    return json.Unmarshal(raw, v)
+}
+
+type mediaItem interface {
+    itemID() string
+}
+
+func reorderMedia[T mediaItem](items []T, ids []string) ([]T, error) {
+    if len(ids) != len(items) {
+        return nil, fmt.Errorf("%w: ids must list all %d items", ErrInvalidMedia, len(items))
+    }
+
+    byID := make(map[string]T, len(items))
+    for _, item := range items {
+        byID[item.itemID()] = item
+    }
+
+    ordered := make([]T, 0, len(items))
+    for _, id := range ids {
+        item, ok := byID[id]
+        if !ok {
+            return nil, fmt.Errorf("%w: unknown or repeated id %s", ErrInvalidMedia, id)
+        }
+        delete(byID, id)
+        ordered = append(ordered, item)
+    }
+
+    return ordered, nil
+}
+
+func removeMedia[T mediaItem](items []T, id string) ([]T, error) {
+    for i, item := range items {
+        if item.itemID() == id {
+            return append(items[:i], items[i+1:]...), nil
+        }
+    }
+
+    return nil, ErrMediaNotFound
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "io"
+    "log"
+    "path"
+    "strings"
+    "unicode/utf8"
+
+    "github.com/aidantrabs/kultur/backend/internal/db"
+    "github.com/aidantrabs/kultur/backend/internal/imaging"
+    "github.com/aidantrabs/kultur/backend/internal/storage"
+    "github.com/google/uuid"
+    "github.com/jackc/pgx/v5"
+    "github.com/jackc/pgx/v5/pgtype"
+    "github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+    ErrMediaAssetNotFound = errors.New("media asset not found")
+    ErrUploadTooLarge     = errors.New("upload too large")
+    ErrUnsupportedMedia   = errors.New("unsupported media")
+)
+
+type MediaService struct {
+    pool           *pgxpool.Pool
+    queries        *db.Queries
+    storage        storage.Storage
+    maxUploadBytes int64
+}
+
+func NewMediaService(pool *pgxpool.Pool, queries *db.Queries, store storage.Storage, maxUploadBytes int64) *MediaService {
+    return &MediaService{
+        pool:           pool,
+        queries:        queries,
+        storage:        store,
+        maxUploadBytes: maxUploadBytes,
+    }
+}
+
+// MediaAsset is an uploaded image with its variants, narrowest first.
+type MediaAsset struct {
+    db.MediaAsset
+    Variants []db.MediaVariant
+}
+
+func (s *MediaService) MaxUploadBytes() int64 {
+    return s.maxUploadBytes
+}
+
+// URL is the public URL of a stored variant.
+func (s *MediaService) URL(key string) string {
+    return s.storage.URL(key)
+}
+
+// Upload checks and processes an image, stores its variants and records
+// them. Only the variants are kept, not the upload itself.
+func (s *MediaService) Upload(ctx context.Context, filename string, r io.Reader) (MediaAsset, error) {
+    data, err := io.ReadAll(io.LimitReader(r, s.maxUploadBytes+1))
+    if err != nil {
+        return MediaAsset{}, err
+    }
+    if int64(len(data)) > s.maxUploadBytes {
+        return MediaAsset{}, fmt.Errorf("%w: the limit is %d bytes", ErrUploadTooLarge, s.maxUploadBytes)
+    }
+
+    source, variants, err := imaging.Process(data, imaging.DefaultWidths)
+    if errors.Is(err, imaging.ErrUnsupportedType) || errors.Is(err, imaging.ErrInvalidImage) {
+        return MediaAsset{}, fmt.Errorf("%w: %v", ErrUnsupportedMedia, err)
+    }
+    if err != nil {
+        return MediaAsset{}, err
+    }
+
+    id := uuid.New()
+    assetID := pgtype.UUID{Bytes: id, Valid: true}
+
+    var stored []string
+    for _, v := range variants {
+        key := fmt.Sprintf("%s/%dw.webp", id, v.Width)
+        if err := s.storage.Put(ctx, key, v.Data, imaging.VariantContentType); err != nil {
+            s.deleteFiles(ctx, stored)
+            return MediaAsset{}, fmt.Errorf("failed to store %s: %w", key, err)
+        }
+        stored = append(stored, key)
+    }
+
+    var asset MediaAsset
+    err = inTx(ctx, s.pool, s.queries, func(q *db.Queries) error {
+        var err error
+        asset.MediaAsset, err = q.CreateMediaAsset(ctx, db.CreateMediaAssetParams{
+            ID:               assetID,
+            OriginalFilename: cleanFilename(filename),
+            ContentType:      source.ContentType,
+            SizeBytes:        int64(len(data)),
+            Width:            int32(source.Width),
+            Height:           int32(source.Height),
+        })
+        if err != nil {
+            return err
+        }
+
+        for i, v := range variants {
+            variant, err := q.CreateMediaVariant(ctx, db.CreateMediaVariantParams{
+                AssetID:     assetID,
+                Width:       int32(v.Width),
+                Height:      int32(v.Height),
+                ContentType: imaging.VariantContentType,
+                SizeBytes:   int64(len(v.Data)),
+                StorageKey:  stored[i],
+            })
+            if err != nil {
+                return err
+            }
+            asset.Variants = append(asset.Variants, variant)
+        }
+        return nil
+    })
+    if err != nil {
+        s.deleteFiles(ctx, stored)
+        return MediaAsset{}, err
+    }
+
+    return asset, nil
+}
+
+func (s *MediaService) Get(ctx context.Context, id pgtype.UUID) (MediaAsset, error) {
+    asset, err := s.queries.GetMediaAsset(ctx, id)
+    if errors.Is(err, pgx.ErrNoRows) {
+        return MediaAsset{}, ErrMediaAssetNotFound
+    }
+    if err != nil {
+        return MediaAsset{}, err
+    }
+
+    variants, err := s.queries.ListMediaVariants(ctx, id)
+    if err != nil {
+        return MediaAsset{}, err
+    }
+
+    return MediaAsset{MediaAsset: asset, Variants: variants}, nil
+}
+
+// Delete removes the asset and its files. Festivals still pointing at its
+// URLs are not changed.
+func (s *MediaService) Delete(ctx context.Context, id pgtype.UUID) error {
+    asset, err := s.Get(ctx, id)
+    if err != nil {
+        return err
+    }
+
+    if err := s.queries.DeleteMediaAsset(ctx, id); err != nil {
+        return err
+    }
+
+    keys := make([]string, 0, len(asset.Variants))
+    for _, v := range asset.Variants {
+        keys = append(keys, v.StorageKey)
+    }
+    s.deleteFiles(ctx, keys)
+
+    return nil
+}
+
+// deleteFiles cleans up stored files, logging failures since the caller is
+// already done with them.
+func (s *MediaService) deleteFiles(ctx context.Context, keys []string) {
+    for _, key := range keys {
+        if err := s.storage.Delete(ctx, key); err != nil {
+            log.Printf("media: failed to delete %s: %v", key, err)
+        }
+    }
+}
+
+func cleanFilename(name string) string {
+    name = strings.TrimSpace(path.Base(strings.ReplaceAll(name, "\\", "/")))
+    if name == "." || name == "/" {
+        return ""
+    }
+
+    for utf8.RuneCountInString(name) > 255 {
+        _, size := utf8.DecodeLastRuneInString(name)
+        name = name[:len(name)-size]
+    }
+
+    return name
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+    "errors"
+    "slices"
+    "testing"
+)
+
+func TestParseVideoURL(t *testing.T) {
+    tests := []struct {
+        url          string
+        wantProvider string
+        wantID       string
+        wantOK       bool
+    }{
+        {"https://www.youtube.com/watch?v=dQw4w9WgXcQ", VideoProviderYouTube, "dQw4w9WgXcQ", true},
+        {"https://m.youtube.com/watch?v=dQw4w9WgXcQ&t=42s", VideoProviderYouTube, "dQw4w9WgXcQ", true},
+        {"http://youtube.com/watch?feature=share&v=dQw4w9WgXcQ", VideoProviderYouTube, "dQw4w9WgXcQ", true},
+        {"https://youtu.be/dQw4w9WgXcQ", VideoProviderYouTube, "dQw4w9WgXcQ", true},
+        {"https://youtu.be/dQw4w9WgXcQ?si=abc123", VideoProviderYouTube, "dQw4w9WgXcQ", true},
+        {"https://www.youtube.com/shorts/dQw4w9WgXcQ", VideoProviderYouTube, "dQw4w9WgXcQ", true},
+        {"https://www.youtube.com/embed/dQw4w9WgXcQ", VideoProviderYouTube, "dQw4w9WgXcQ", true},
+        {"https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ", VideoProviderYouTube, "dQw4w9WgXcQ", true},
+        {"https://www.youtube.com/live/dQw4w9WgXcQ", VideoProviderYouTube, "dQw4w9WgXcQ", true},
+        {"https://WWW.YouTube.com/watch?v=dQw4w9WgXcQ", VideoProviderYouTube, "dQw4w9WgXcQ", true},
+        {"https://vimeo.com/76979871", VideoProviderVimeo, "76979871", true},
+        {"https://player.vimeo.com/video/76979871", VideoProviderVimeo, "76979871", true},
+        {"https://player.vimeo.com/video/76979871?h=abc", VideoProviderVimeo, "76979871", true},
+
+        {"https://player.vimeo.com/76979871", "", "", false},
+        {"https://vimeo.com/channels/staffpicks/76979871", "", "", false},
+        {"https://www.youtube.com/channel/UC123", "", "", false},
+        {"https://www.youtube.com/shorts/dQw4w9WgXcQ/extra", "", "", false},
+        {"https://www.dailymotion.com/video/x7tgad0", "", "", false},
+        {"https://youtube.com.evil.example/watch?v=dQw4w9WgXcQ", "", "", false},
+        {"https://evil.example/youtube.com/watch?v=dQw4w9WgXcQ", "", "", false},
+        {"javascript:alert(1)", "", "", false},
+        {"javascript://youtube.com/watch?v=dQw4w9WgXcQ", "", "", false},
+        {"ftp://youtube.com/watch?v=dQw4w9WgXcQ", "", "", false},
+        {"//youtube.com/watch?v=dQw4w9WgXcQ", "", "", false},
+        {"dQw4w9WgXcQ", "", "", false},
+        {"", "", "", false},
+    }
+
+    for _, tt := range tests {
+        provider, id, ok := parseVideoURL(tt.url)
+        if provider != tt.wantProvider || id != tt.wantID || ok != tt.wantOK {
+            t.Errorf("parseVideoURL(%q) = %q, %q, %v, want %q, %q, %v",
+                tt.url, provider, id, ok, tt.wantProvider, tt.wantID, tt.wantOK)
+        }
+    }
+}
+
+func TestVideoEmbedParams(t *testing.T) {
+    tests := []struct {
+        name    string
+        params  VideoEmbedParams
+        wantErr bool
+    }{
+        {"youtube url", VideoEmbedParams{URL: "https://youtu.be/dQw4w9WgXcQ", Title: "Jouvert"}, false},
+        {"provider and id", VideoEmbedParams{Provider: "Vimeo", VideoID: "76979871", Title: "Jouvert"}, false},
+        {"watch link without v", VideoEmbedParams{URL: "https://www.youtube.com/watch", Title: "Jouvert"}, true},
+        {"share link without id", VideoEmbedParams{URL: "https://youtu.be/", Title: "Jouvert"}, true},
+        {"javascript url", VideoEmbedParams{URL: "javascript:alert(1)", Title: "Jouvert"}, true},
+        {"other host", VideoEmbedParams{URL: "https://www.dailymotion.com/video/x7tgad0", Title: "Jouvert"}, true},
+        {"unknown provider", VideoEmbedParams{Provider: "dailymotion", VideoID: "x7tgad0", Title: "Jouvert"}, true},
+        {"invalid youtube id", VideoEmbedParams{Provider: "youtube", VideoID: "dQw4w9WgXcQ\"><script>", Title: "Jouvert"}, true},
+        {"missing title", VideoEmbedParams{URL: "https://youtu.be/dQw4w9WgXcQ", Title: "  "}, true},
+    }
+
+    for _, tt := range tests {
+        _, err := tt.params.embed()
+        if tt.wantErr && !errors.Is(err, ErrInvalidMedia) {
+            t.Errorf("%s: err = %v, want ErrInvalidMedia", tt.name, err)
+        }
+        if !tt.wantErr && err != nil {
+            t.Errorf("%s: %v", tt.name, err)
+        }
+    }
+}
+
+func galleryIDs(images []GalleryImage) []string {
+    ids := make([]string, len(images))
+    for i, img := range images {
+        ids[i] = img.ID
+    }
+
+    return ids
+}
+
+func TestReorderMedia(t *testing.T) {
+    images := []GalleryImage{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+
+    tests := []struct {
+        name    string
+        ids     []string
+        want    []string
+        wantErr bool
+    }{
+        {"same order", []string{"a", "b", "c"}, []string{"a", "b", "c"}, false},
+        {"reversed", []string{"c", "b", "a"}, []string{"c", "b", "a"}, false},
+        {"missing id", []string{"c", "a"}, nil, true},
+        {"duplicate id", []string{"a", "a", "c"}, nil, true},
+        {"unknown id", []string{"a", "b", "x"}, nil, true},
+        {"extra id", []string{"a", "b", "c", "x"}, nil, true},
+        {"empty", []string{}, nil, true},
+    }
+
+    for _, tt := range tests {
+        got, err := reorderMedia(images, tt.ids)
+        if tt.wantErr {
+            if !errors.Is(err, ErrInvalidMedia) {
+                t.Errorf("%s: err = %v, want ErrInvalidMedia", tt.name, err)
+            }
+            continue
+        }
+
+        if err != nil {
+            t.Errorf("%s: %v", tt.name, err)
+            continue
+        }
+        if ids := galleryIDs(got); !slices.Equal(ids, tt.want) {
+            t.Errorf("%s: got %v, want %v", tt.name, ids, tt.want)
+        }
+    }
+
+    if got, err := reorderMedia([]VideoEmbed{}, nil); err != nil || len(got) != 0 {
+        t.Errorf("reordering no items = %v, %v, want an empty list", got, err)
+    }
+}
+
+func TestRemoveMedia(t *testing.T) {
+    tests := []struct {
+        name    string
+        id      string
+        want    []string
+        wantErr error
+    }{
+        {"first", "a", []string{"b", "c"}, nil},
+        {"middle", "b", []string{"a", "c"}, nil},
+        {"last", "c", []string{"a", "b"}, nil},
+        {"missing", "x", nil, ErrMediaNotFound},
+        {"empty id", "", nil, ErrMediaNotFound},
+    }
+
+    for _, tt := range tests {
+        images := []GalleryImage{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+
+        got, err := removeMedia(images, tt.id)
+        if !errors.Is(err, tt.wantErr) {
+            t.Errorf("%s: err = %v, want %v", tt.name, err, tt.wantErr)
+            continue
+        }
+        if err == nil && !slices.Equal(galleryIDs(got), tt.want) {
+            t.Errorf("%s: got %v, want %v", tt.name, galleryIDs(got), tt.want)
+        }
+    }
+}
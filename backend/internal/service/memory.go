@@ -2,24 +2,47 @@ package service
 
 import (
     "context"
+    "encoding/json"
     "errors"
+    "fmt"
+    "log"
+    "regexp"
+    "slices"
+    "strconv"
+    "time"
 
     "github.com/aidantrabs/kultur/backend/internal/db"
+    "github.com/aidantrabs/kultur/backend/internal/email"
+    "github.com/aidantrabs/kultur/backend/internal/screening"
+    "github.com/google/uuid"
     "github.com/jackc/pgx/v5"
     "github.com/jackc/pgx/v5/pgtype"
+    "github.com/jackc/pgx/v5/pgxpool"
 )
 
-var ErrMemoryNotFound = errors.New("memory not found")
+var (
+    ErrMemoryNotFound       = errors.New("memory not found")
+    ErrFestivalNotPublished = errors.New("festival is not published")
+    ErrInvalidMemoryYear    = errors.New("invalid year of memory")
+)
+
+var memoryYearPattern = regexp.MustCompile(`^[1-9][0-9]{3}$`)
 
 type MemoryService struct {
+    pool            *pgxpool.Pool
     queries         *db.Queries
     festivalService *FestivalService
+    email           *email.Service
+    screener        *screening.Pipeline
 }
 
-func NewMemoryService(queries *db.Queries, festivalService *FestivalService) *MemoryService {
+func NewMemoryService(pool *pgxpool.Pool, queries *db.Queries, festivalService *FestivalService, emailSvc *email.Service, screener *screening.Pipeline) *MemoryService {
     return &MemoryService{
+        pool:            pool,
         queries:         queries,
         festivalService: festivalService,
+        email:           emailSvc,
+        screener:        screener,
     }
 }
 
@@ -32,8 +55,64 @@ func (s *MemoryService) ListByFestivalSlug(ctx context.Context, slug string) ([]
     return s.queries.ListMemoriesByFestival(ctx, festival.ID)
 }
 
-func (s *MemoryService) ListAll(ctx context.Context) ([]db.Memory, error) {
-    return s.queries.ListAllMemories(ctx)
+type ListMemoriesParams struct {
+    Status     string
+    FestivalID pgtype.UUID
+    From       *time.Time
+    To         *time.Time
+    Cursor     string
+    Limit      int
+}
+
+// List returns one page of memories, newest first, with the total number
+// matching the filters.
+func (s *MemoryService) List(ctx context.Context, params ListMemoriesParams) (Page[db.Memory], error) {
+    if params.Status != "" && !slices.Contains(memoryStatuses, params.Status) {
+        return Page[db.Memory]{}, fmt.Errorf("%w: status must be pending, approved or rejected", ErrInvalidFilter)
+    }
+
+    from, to, err := dateRange(params.From, params.To)
+    if err != nil {
+        return Page[db.Memory]{}, err
+    }
+
+    var after timeCursor
+    if err := after.decode(params.Cursor); err != nil {
+        return Page[db.Memory]{}, err
+    }
+
+    status := pgtype.Text{String: params.Status, Valid: params.Status != ""}
+    size := pageSize(params.Limit)
+
+    rows, err := s.queries.ListMemoriesPage(ctx, db.ListMemoriesPageParams{
+        Status:           status,
+        FestivalID:       params.FestivalID,
+        SubmittedFrom:    from,
+        SubmittedTo:      to,
+        AfterID:          after.ID,
+        AfterSubmittedAt: after.At,
+        MaxResults:       int32(size + 1),
+    })
+    if err != nil {
+        return Page[db.Memory]{}, err
+    }
+
+    total, err := s.queries.CountMemories(ctx, db.CountMemoriesParams{
+        Status:        status,
+        FestivalID:    params.FestivalID,
+        SubmittedFrom: from,
+        SubmittedTo:   to,
+    })
+    if err != nil {
+        return Page[db.Memory]{}, err
+    }
+
+    page, err := newPage(rows, size, func(last db.Memory) (string, error) {
+        return encodeCursor(timeCursor{At: last.SubmittedAt, ID: last.ID})
+    })
+    page.Total = &total
+
+    return page, err
 }
 
 func (s *MemoryService) GetByID(ctx context.Context, id pgtype.UUID) (db.Memory, error) {
@@ -51,28 +130,121 @@ type CreateMemoryParams struct {
     AuthorEmail  string
     Content      string
     YearOfMemory string
+    // NotifyAuthor emails the author once the memory is approved or rejected
+    NotifyAuthor bool
+    // Honeypot is the hidden form field only bots fill in
+    Honeypot string
 }
 
+// screeningReviewer is the reviewer recorded for automatic rejections.
+const screeningReviewer = "screening"
+
+// Create screens the submission and stores it with its score. Flagged
+// memories jump the moderation queue and ones scoring high enough are
+// rejected straight away, which is recorded like any other review.
 func (s *MemoryService) Create(ctx context.Context, params CreateMemoryParams) (db.Memory, error) {
-    return s.queries.CreateMemory(ctx, db.CreateMemoryParams{
-        FestivalID:   params.FestivalID,
-        AuthorName:   pgtype.Text{String: params.AuthorName, Valid: params.AuthorName != ""},
-        AuthorEmail:  pgtype.Text{String: params.AuthorEmail, Valid: params.AuthorEmail != ""},
-        Content:      params.Content,
-        YearOfMemory: pgtype.Text{String: params.YearOfMemory, Valid: params.YearOfMemory != ""},
+    if err := validateMemoryYear(params.YearOfMemory); err != nil {
+        return db.Memory{}, err
+    }
+
+    if params.AuthorEmail != "" {
+        address, err := parseEmail(params.AuthorEmail)
+        if err != nil {
+            return db.Memory{}, err
+        }
+        params.AuthorEmail = address
+    }
+
+    festival, err := s.festivalService.GetByID(ctx, params.FestivalID)
+    if err != nil {
+        return db.Memory{}, err
+    }
+    if !festival.IsPublished.Bool {
+        return db.Memory{}, ErrFestivalNotPublished
+    }
+
+    verdict, err := s.screener.Screen(ctx, screening.Submission{
+        Content:     params.Content,
+        AuthorName:  params.AuthorName,
+        AuthorEmail: params.AuthorEmail,
+        Honeypot:    params.Honeypot,
     })
-}
+    if err != nil {
+        return db.Memory{}, err
+    }
 
-func (s *MemoryService) UpdateStatus(ctx context.Context, id pgtype.UUID, status string) error {
-    _, err := s.GetByID(ctx, id)
+    results, err := json.Marshal(verdict.Results)
     if err != nil {
-        return err
+        return db.Memory{}, err
     }
 
-    return s.queries.UpdateMemoryStatus(ctx, db.UpdateMemoryStatusParams{
-        ID:     id,
-        Status: pgtype.Text{String: status, Valid: true},
+    authorToken, err := generateToken()
+    if err != nil {
+        return db.Memory{}, err
+    }
+
+    status := MemoryStatusPending
+    if verdict.Decision == screening.Reject {
+        status = MemoryStatusRejected
+    }
+
+    var memory db.Memory
+    err = s.inTx(ctx, func(q *db.Queries) error {
+        memory, err = q.CreateMemory(ctx, db.CreateMemoryParams{
+            FestivalID:       params.FestivalID,
+            AuthorName:       pgtype.Text{String: params.AuthorName, Valid: params.AuthorName != ""},
+            AuthorEmail:      pgtype.Text{String: params.AuthorEmail, Valid: params.AuthorEmail != ""},
+            Content:          params.Content,
+            YearOfMemory:     pgtype.Text{String: params.YearOfMemory, Valid: params.YearOfMemory != ""},
+            NotifyAuthor:     params.NotifyAuthor,
+            AuthorToken:      authorToken,
+            Status:           pgtype.Text{String: status, Valid: true},
+            ContentHash:      pgtype.Text{String: screening.ContentHash(params.Content), Valid: true},
+            ScreeningScore:   int32(verdict.Score),
+            ScreeningResults: results,
+            Flagged:          verdict.Decision != screening.Accept,
+        })
+        if err != nil || status != MemoryStatusRejected {
+            return err
+        }
+
+        _, err = q.CreateMemoryReview(ctx, db.CreateMemoryReviewParams{
+            MemoryID:   memory.ID,
+            FromStatus: pgtype.Text{String: MemoryStatusPending, Valid: true},
+            ToStatus:   MemoryStatusRejected,
+            Reviewer:   screeningReviewer,
+            Reason:     pgtype.Text{String: fmt.Sprintf("automatic: screening score %d", verdict.Score), Valid: true},
+            Notes:      pgtype.Text{String: verdict.Reasons(), Valid: true},
+        })
+        return err
     })
+    if err != nil {
+        return db.Memory{}, err
+    }
+
+    if verdict.Decision != screening.Accept {
+        log.Printf("memories: %s %s with score %d: %s", verdict.Decision, uuid.UUID(memory.ID.Bytes), verdict.Score, verdict.Reasons())
+    }
+
+    return memory, nil
+}
+
+// validateMemoryYear accepts an empty year or a 4-digit year that isn't in
+// the future.
+func validateMemoryYear(year string) error {
+    if year == "" {
+        return nil
+    }
+
+    if !memoryYearPattern.MatchString(year) {
+        return fmt.Errorf("%w: year_of_memory must be a 4-digit year", ErrInvalidMemoryYear)
+    }
+
+    if y, _ := strconv.Atoi(year); y > time.Now().Year() {
+        return fmt.Errorf("%w: year_of_memory can't be in the future", ErrInvalidMemoryYear)
+    }
+
+    return nil
 }
 
 func (s *MemoryService) Delete(ctx context.Context, id pgtype.UUID) error {
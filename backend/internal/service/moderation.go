@@ -0,0 +1,300 @@
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "slices"
+    "strings"
+
+    "github.com/aidantrabs/kultur/backend/internal/db"
+    "github.com/google/uuid"
+    "github.com/jackc/pgx/v5"
+    "github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrInvalidReview = errors.New("invalid review")
+
+const (
+    MemoryStatusPending  = "pending"
+    MemoryStatusApproved = "approved"
+    MemoryStatusRejected = "rejected"
+)
+
+var memoryStatuses = []string{MemoryStatusPending, MemoryStatusApproved, MemoryStatusRejected}
+
+// MaxBulkReview is the most memories one bulk review can change.
+const MaxBulkReview = 100
+
+// ReviewParams describes a status change. Reviewer is the admin making it,
+// taken from their API key. A reason is required to reject. Message is shown
+// to the author in the rejection email, while Reason and Notes stay internal.
+type ReviewParams struct {
+    Status   string
+    Reviewer string
+    Reason   string
+    Notes    string
+    Message  string
+}
+
+func (p ReviewParams) validate() error {
+    if !slices.Contains(memoryStatuses, p.Status) {
+        return fmt.Errorf("%w: status must be approved, rejected, or pending", ErrInvalidReview)
+    }
+
+    if strings.TrimSpace(p.Reviewer) == "" {
+        return fmt.Errorf("%w: reviewer is required", ErrInvalidReview)
+    }
+
+    if p.Status == MemoryStatusRejected && strings.TrimSpace(p.Reason) == "" {
+        return fmt.Errorf("%w: reason is required when rejecting", ErrInvalidReview)
+    }
+
+    return nil
+}
+
+type BulkReviewResult struct {
+    Reviewed []pgtype.UUID
+    NotFound []pgtype.UUID
+}
+
+// Review changes a memory's status and records the transition in
+// memory_reviews. Setting the status it already has changes nothing. The
+// author is emailed once the change is committed.
+func (s *MemoryService) Review(ctx context.Context, id pgtype.UUID, params ReviewParams) (db.Memory, error) {
+    if err := params.validate(); err != nil {
+        return db.Memory{}, err
+    }
+
+    var (
+        memory db.Memory
+        from   pgtype.Text
+    )
+    err := s.inTx(ctx, func(q *db.Queries) error {
+        var err error
+        memory, from, err = review(ctx, q, id, params)
+        return err
+    })
+    if err != nil {
+        return db.Memory{}, err
+    }
+
+    s.notifyAuthor(ctx, from, memory, params.Message)
+
+    return memory, nil
+}
+
+// BulkReview applies the same review to every memory in ids in one
+// transaction. Unknown ids are reported rather than failing the batch.
+func (s *MemoryService) BulkReview(ctx context.Context, ids []pgtype.UUID, params ReviewParams) (BulkReviewResult, error) {
+    if err := params.validate(); err != nil {
+        return BulkReviewResult{}, err
+    }
+
+    if len(ids) == 0 || len(ids) > MaxBulkReview {
+        return BulkReviewResult{}, fmt.Errorf("%w: ids must have between 1 and %d entries", ErrInvalidReview, MaxBulkReview)
+    }
+
+    type change struct {
+        from   pgtype.Text
+        memory db.Memory
+    }
+
+    var changes []change
+    result := BulkReviewResult{Reviewed: []pgtype.UUID{}, NotFound: []pgtype.UUID{}}
+    seen := make(map[[16]byte]bool, len(ids))
+    err := s.inTx(ctx, func(q *db.Queries) error {
+        for _, id := range ids {
+            if seen[id.Bytes] {
+                continue
+            }
+            seen[id.Bytes] = true
+
+            memory, from, err := review(ctx, q, id, params)
+            if errors.Is(err, ErrMemoryNotFound) {
+                result.NotFound = append(result.NotFound, id)
+                continue
+            }
+            if err != nil {
+                return err
+            }
+
+            changes = append(changes, change{from: from, memory: memory})
+            result.Reviewed = append(result.Reviewed, id)
+        }
+        return nil
+    })
+    if err != nil {
+        return BulkReviewResult{}, err
+    }
+
+    for _, c := range changes {
+        s.notifyAuthor(ctx, c.from, c.memory, params.Message)
+    }
+
+    return result, nil
+}
+
+// review applies params to one memory and returns it with the status it had
+// before.
+func review(ctx context.Context, q *db.Queries, id pgtype.UUID, params ReviewParams) (db.Memory, pgtype.Text, error) {
+    memory, err := q.GetMemoryForUpdate(ctx, id)
+    if errors.Is(err, pgx.ErrNoRows) {
+        return db.Memory{}, pgtype.Text{}, ErrMemoryNotFound
+    }
+    if err != nil {
+        return db.Memory{}, pgtype.Text{}, err
+    }
+
+    if memory.Status.String == params.Status {
+        return memory, memory.Status, nil
+    }
+
+    updated, err := q.UpdateMemoryStatus(ctx, db.UpdateMemoryStatusParams{
+        ID:     id,
+        Status: pgtype.Text{String: params.Status, Valid: true},
+    })
+    if err != nil {
+        return db.Memory{}, pgtype.Text{}, err
+    }
+
+    if _, err := q.CreateMemoryReview(ctx, db.CreateMemoryReviewParams{
+        MemoryID:      id,
+        FromStatus:    memory.Status,
+        ToStatus:      params.Status,
+        Reviewer:      strings.TrimSpace(params.Reviewer),
+        Reason:        pgtype.Text{String: params.Reason, Valid: params.Reason != ""},
+        Notes:         pgtype.Text{String: params.Notes, Valid: params.Notes != ""},
+        AuthorMessage: pgtype.Text{String: params.Message, Valid: params.Message != ""},
+    }); err != nil {
+        return db.Memory{}, pgtype.Text{}, err
+    }
+
+    return updated, memory.Status, nil
+}
+
+// notifyAuthor emails the author when a pending memory is approved or
+// rejected, unless they opted out. Failures are logged, the review stands.
+func (s *MemoryService) notifyAuthor(ctx context.Context, from pgtype.Text, memory db.Memory, message string) {
+    if from.Valid && from.String != MemoryStatusPending {
+        return
+    }
+
+    var kind string
+    switch memory.Status.String {
+    case MemoryStatusApproved:
+        kind, message = "memory-approved", ""
+    case MemoryStatusRejected:
+        kind = "memory-rejected"
+    default:
+        return
+    }
+
+    if !memory.NotifyAuthor || memory.AuthorEmail.String == "" {
+        return
+    }
+
+    id := uuid.UUID(memory.ID.Bytes)
+
+    optedOut, err := s.queries.IsMemoryAuthorOptedOut(ctx, memory.AuthorEmail.String)
+    if err != nil {
+        log.Printf("memories: failed to check opt-out for %s: %v", id, err)
+        return
+    }
+    if optedOut {
+        return
+    }
+
+    festival, err := s.festivalService.GetByID(ctx, memory.FestivalID)
+    if err != nil {
+        log.Printf("memories: failed to load festival for %s: %v", id, err)
+        return
+    }
+
+    data := s.email.MemoryReviewData(memory.AuthorName.String, festival.Name, festival.Slug, id.String(), memory.AuthorToken, message)
+    if err := s.email.SendMemoryReview(ctx, memory.AuthorEmail.String, kind, data); err != nil {
+        log.Printf("memories: failed to notify author of %s: %v", id, err)
+    }
+}
+
+// OptOutAuthor stops review emails to the author of the memory with the
+// given author token, for this and all their other memories.
+func (s *MemoryService) OptOutAuthor(ctx context.Context, token string) error {
+    memory, err := s.queries.GetMemoryByAuthorToken(ctx, token)
+    if errors.Is(err, pgx.ErrNoRows) {
+        return ErrInvalidToken
+    }
+    if err != nil {
+        return err
+    }
+
+    if memory.AuthorEmail.String == "" {
+        return nil
+    }
+
+    return s.queries.CreateMemoryAuthorOptout(ctx, memory.AuthorEmail.String)
+}
+
+func (s *MemoryService) inTx(ctx context.Context, fn func(q *db.Queries) error) error {
+    return inTx(ctx, s.pool, s.queries, fn)
+}
+
+// queueCursor continues the moderation queue, which lists flagged memories
+// before the rest.
+type queueCursor struct {
+    Flagged bool `json:"flagged"`
+    timeCursor
+}
+
+// Queue returns pending memories, the ones flagged by screening first and
+// then oldest first, with the number still waiting in Total.
+func (s *MemoryService) Queue(ctx context.Context, cursor string, limit int) (Page[db.Memory], error) {
+    var after queueCursor
+    if cursor != "" {
+        if err := decodeCursor(cursor, &after); err != nil {
+            return Page[db.Memory]{}, err
+        }
+        if !after.At.Valid || !after.ID.Valid {
+            return Page[db.Memory]{}, ErrInvalidCursor
+        }
+    }
+
+    size := pageSize(limit)
+
+    rows, err := s.queries.ListPendingMemories(ctx, db.ListPendingMemoriesParams{
+        AfterID:          after.ID,
+        AfterFlagged:     pgtype.Bool{Bool: after.Flagged, Valid: after.ID.Valid},
+        AfterSubmittedAt: after.At,
+        MaxResults:       int32(size + 1),
+    })
+    if err != nil {
+        return Page[db.Memory]{}, err
+    }
+
+    total, err := s.queries.CountMemories(ctx, db.CountMemoriesParams{
+        Status: pgtype.Text{String: MemoryStatusPending, Valid: true},
+    })
+    if err != nil {
+        return Page[db.Memory]{}, err
+    }
+
+    page, err := newPage(rows, size, func(last db.Memory) (string, error) {
+        return encodeCursor(queueCursor{
+            Flagged:    last.Flagged,
+            timeCursor: timeCursor{At: last.SubmittedAt, ID: last.ID},
+        })
+    })
+    page.Total = &total
+
+    return page, err
+}
+
+// History lists every status change of a memory, oldest first.
+func (s *MemoryService) History(ctx context.Context, id pgtype.UUID) ([]db.MemoryReview, error) {
+    if _, err := s.GetByID(ctx, id); err != nil {
+        return nil, err
+    }
+
+    return s.queries.ListMemoryReviews(ctx, id)
+}
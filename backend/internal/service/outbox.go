@@ -0,0 +1,157 @@
+package service
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "log"
+    "time"
+
+    "github.com/aidantrabs/kultur/backend/internal/db"
+    "github.com/aidantrabs/kultur/backend/internal/email"
+    "github.com/google/uuid"
+    "github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+    OutboxStatusPending = "pending"
+    OutboxStatusSent    = "sent"
+    OutboxStatusDead    = "dead"
+)
+
+const (
+    outboxBatchSize   = 20
+    outboxBaseBackoff = 30 * time.Second
+    outboxMaxBackoff  = 6 * time.Hour
+)
+
+// OutboxService stores outgoing email in email_outbox and delivers it in the
+// background, retrying failures with exponential backoff.
+type OutboxService struct {
+    queries     *db.Queries
+    email       *email.Service
+    maxAttempts int
+}
+
+func NewOutboxService(queries *db.Queries, emailSvc *email.Service, maxAttempts int) *OutboxService {
+    return &OutboxService{
+        queries:     queries,
+        email:       emailSvc,
+        maxAttempts: maxAttempts,
+    }
+}
+
+func (s *OutboxService) Enqueue(ctx context.Context, msg email.Message) error {
+    headers, err := json.Marshal(msg.Headers)
+    if err != nil {
+        return err
+    }
+
+    _, err = s.queries.EnqueueEmail(ctx, db.EnqueueEmailParams{
+        ToEmail:  msg.To,
+        Subject:  msg.Subject,
+        HtmlBody: msg.HTML,
+        TextBody: pgtype.Text{String: msg.Text, Valid: msg.Text != ""},
+        Headers:  headers,
+    })
+
+    return err
+}
+
+// Run processes due messages every interval until ctx is cancelled.
+func (s *OutboxService) Run(ctx context.Context, interval time.Duration) {
+    go func() {
+        ticker := time.NewTicker(interval)
+        defer ticker.Stop()
+
+        for {
+            if err := s.ProcessDue(ctx); err != nil {
+                log.Printf("outbox: %v", err)
+            }
+
+            select {
+            case <-ctx.Done():
+                return
+            case <-ticker.C:
+            }
+        }
+    }()
+}
+
+// ProcessDue delivers every message whose next attempt is due. Claiming a
+// batch leases it for a few minutes, so a crashed worker's batch is picked up
+// again instead of being lost.
+func (s *OutboxService) ProcessDue(ctx context.Context) error {
+    for {
+        batch, err := s.queries.ClaimDueEmails(ctx, outboxBatchSize)
+        if err != nil {
+            return fmt.Errorf("failed to claim emails: %w", err)
+        }
+
+        for _, item := range batch {
+            s.deliver(ctx, item)
+        }
+
+        if len(batch) < outboxBatchSize {
+            return nil
+        }
+    }
+}
+
+func (s *OutboxService) deliver(ctx context.Context, item db.EmailOutbox) {
+    id := uuid.UUID(item.ID.Bytes)
+
+    var headers map[string]string
+    if len(item.Headers) > 0 {
+        if err := json.Unmarshal(item.Headers, &headers); err != nil {
+            log.Printf("outbox: ignoring invalid headers for %s: %v", id, err)
+        }
+    }
+
+    messageID, err := s.email.Deliver(ctx, email.Message{
+        IdempotencyKey: "outbox/" + id.String(),
+        To:             item.ToEmail,
+        Subject:        item.Subject,
+        HTML:           item.HtmlBody,
+        Text:           item.TextBody.String,
+        Headers:        headers,
+    })
+    if err == nil {
+        if err := s.queries.MarkEmailSent(ctx, db.MarkEmailSentParams{
+            ID:                item.ID,
+            ProviderMessageID: pgtype.Text{String: messageID, Valid: messageID != ""},
+        }); err != nil {
+            log.Printf("outbox: failed to mark %s sent: %v", id, err)
+        }
+        return
+    }
+
+    status := OutboxStatusPending
+    if int(item.Attempts) >= s.maxAttempts {
+        status = OutboxStatusDead
+    }
+
+    log.Printf("outbox: attempt %d for %s failed (%s): %v", item.Attempts, id, status, err)
+
+    if err := s.queries.MarkEmailFailed(ctx, db.MarkEmailFailedParams{
+        ID:            item.ID,
+        Status:        status,
+        LastError:     pgtype.Text{String: err.Error(), Valid: true},
+        NextAttemptAt: pgtype.Timestamptz{Time: time.Now().Add(outboxBackoff(int(item.Attempts))), Valid: true},
+    }); err != nil {
+        log.Printf("outbox: failed to record failure for %s: %v", id, err)
+    }
+}
+
+// outboxBackoff doubles the wait after every failed attempt.
+func outboxBackoff(attempts int) time.Duration {
+    backoff := outboxBaseBackoff
+    for i := 1; i < attempts; i++ {
+        backoff *= 2
+        if backoff >= outboxMaxBackoff {
+            return outboxMaxBackoff
+        }
+    }
+
+    return backoff
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+    "encoding/base64"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "time"
+
+    "github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+    ErrInvalidCursor = errors.New("invalid cursor")
+    ErrInvalidFilter = errors.New("invalid filter")
+)
+
+const (
+    DefaultPageSize = 50
+    MaxPageSize     = 100
+)
+
+// Page is the envelope every paginated list endpoint responds with.
+// NextCursor is empty on the last page. Total, the number of rows matching
+// the filters across all pages, is only counted for admin lists.
+type Page[T any] struct {
+    Data       []T    `json:"data"`
+    NextCursor string `json:"nextCursor,omitempty"`
+    HasMore    bool   `json:"hasMore"`
+    Total      *int64 `json:"total,omitempty"`
+}
+
+// MapPage converts the rows of a page, keeping its cursor and counts.
+func MapPage[T, U any](p Page[T], f func(T) U) Page[U] {
+    data := make([]U, len(p.Data))
+    for i, row := range p.Data {
+        data[i] = f(row)
+    }
+
+    return Page[U]{Data: data, NextCursor: p.NextCursor, HasMore: p.HasMore, Total: p.Total}
+}
+
+// timeCursor continues a list ordered newest first by a timestamp and id.
+type timeCursor struct {
+    At pgtype.Timestamptz `json:"at"`
+    ID pgtype.UUID        `json:"id"`
+}
+
+// pageSize clamps a requested limit to 1..MaxPageSize, using the default
+// when none was given.
+func pageSize(limit int) int {
+    switch {
+    case limit <= 0:
+        return DefaultPageSize
+    case limit > MaxPageSize:
+        return MaxPageSize
+    default:
+        return limit
+    }
+}
+
+// newPage trims the extra row fetched to detect a following page and builds
+// the cursor from the last row that is kept.
+func newPage[T any](rows []T, size int, cursor func(T) (string, error)) (Page[T], error) {
+    page := Page[T]{Data: rows}
+    if len(rows) <= size {
+        return page, nil
+    }
+
+    page.Data = rows[:size]
+    page.HasMore = true
+
+    next, err := cursor(page.Data[size-1])
+    if err != nil {
+        return Page[T]{}, err
+    }
+    page.NextCursor = next
+
+    return page, nil
+}
+
+// Cursors are opaque to clients: base64url encoded JSON of the sort keys.
+func encodeCursor(v any) (string, error) {
+    b, err := json.Marshal(v)
+    if err != nil {
+        return "", err
+    }
+
+    return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeCursor(s string, v any) error {
+    b, err := base64.RawURLEncoding.DecodeString(s)
+    if err != nil {
+        return ErrInvalidCursor
+    }
+
+    if err := json.Unmarshal(b, v); err != nil {
+        return ErrInvalidCursor
+    }
+
+    return nil
+}
+
+// decode reads a timeCursor, leaving both values null when s is empty.
+func (c *timeCursor) decode(s string) error {
+    if s == "" {
+        return nil
+    }
+
+    if err := decodeCursor(s, c); err != nil {
+        return err
+    }
+
+    if !c.At.Valid || !c.ID.Valid {
+        return ErrInvalidCursor
+    }
+
+    return nil
+}
+
+// dateRange converts optional from/to filters, both inclusive.
+func dateRange(from, to *time.Time) (pgtype.Date, pgtype.Date, error) {
+    var f, t pgtype.Date
+    if from != nil {
+        f = pgtype.Date{Time: *from, Valid: true}
+    }
+    if to != nil {
+        t = pgtype.Date{Time: *to, Valid: true}
+    }
+
+    if f.Valid && t.Valid && t.Time.Before(f.Time) {
+        return f, t, fmt.Errorf("%w: to is before from", ErrInvalidFilter)
+    }
+
+    return f, t, nil
+}
+
+func optionalBool(b *bool) pgtype.Bool {
+    if b == nil {
+        return pgtype.Bool{}
+    }
+
+    return pgtype.Bool{Bool: *b, Valid: true}
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "time"
+
+    "github.com/aidantrabs/kultur/backend/internal/db"
+    "github.com/aidantrabs/kultur/backend/internal/email"
+    "github.com/jackc/pgx/v5"
+    "github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrUnknownEmailType = errors.New("unknown email type")
+
+const (
+    previewToken            = "preview-token"
+    previewMemoryID         = "00000000-0000-0000-0000-000000000000"
+    previewModeratorMessage = "Thanks for writing in! Your memory reads more like a review of a fete than a memory of the festival itself. We'd love to hear about the festival if you'd like to try again."
+)
+
+// fixture festivals used when a preview isn't given a slug
+var previewFestivals = []email.FestivalDigestItem{
+    {
+        Name:     "Trinidad Carnival",
+        Slug:     "carnival",
+        Date:     "February 16-17, 2026",
+        Heritage: "Mixed Heritage",
+        Region:   "Nationwide",
+    },
+    {
+        Name:     "Hosay",
+        Slug:     "hosay",
+        Date:     "February 20, 2026",
+        Heritage: "Indian Heritage",
+        Region:   "St. James",
+    },
+    {
+        Name:     "Phagwa",
+        Slug:     "phagwa",
+        Date:     "March 14, 2026",
+        Heritage: "Indian Heritage",
+        Region:   "Central Trinidad",
+    },
+}
+
+// PreviewService renders emails exactly as they would be sent, without
+// sending or queueing them.
+type PreviewService struct {
+    queries         *db.Queries
+    festivals       *FestivalService
+    email           *email.Service
+    confirmationTTL time.Duration
+}
+
+func NewPreviewService(queries *db.Queries, festivalService *FestivalService, emailSvc *email.Service, confirmationTTL time.Duration) *PreviewService {
+    return &PreviewService{
+        queries:         queries,
+        festivals:       festivalService,
+        email:           emailSvc,
+        confirmationTTL: confirmationTTL,
+    }
+}
+
+// PreviewParams picks live data for a preview. Anything left empty falls
+// back to fixtures.
+type PreviewParams struct {
+    Slug         string
+    SubscriberID pgtype.UUID
+    // DaysUntil is nil for the default lead time, 0 previews the day-of email
+    DaysUntil *int
+}
+
+func (s *PreviewService) Render(ctx context.Context, emailType string, params PreviewParams) (email.Message, error) {
+    to := "preview@example.com"
+
+    if params.SubscriberID.Valid {
+        sub, err := s.queries.GetSubscriptionByID(ctx, params.SubscriberID)
+        if errors.Is(err, pgx.ErrNoRows) {
+            return email.Message{}, ErrSubscriptionNotFound
+        }
+        if err != nil {
+            return email.Message{}, err
+        }
+
+        // only the address is used, links keep the placeholder token so a
+        // preview never hands out a working unsubscribe or confirmation link
+        to = sub.Email
+    }
+
+    var data any
+    switch emailType {
+    case "confirmation":
+        data = s.email.ConfirmationData(previewToken, s.confirmationTTL)
+
+    case "welcome":
+        data = s.email.WelcomeData(previewToken)
+
+    case "digest":
+        items, err := s.digestItems(ctx, params)
+        if err != nil {
+            return email.Message{}, err
+        }
+        data = s.email.DigestData(items, previewToken)
+
+    case "reminder":
+        name, slug := previewFestivals[0].Name, previewFestivals[0].Slug
+        if params.Slug != "" {
+            festival, err := s.festivals.GetBySlug(ctx, params.Slug)
+            if err != nil {
+                return email.Message{}, err
+            }
+            name, slug = festival.Name, festival.Slug
+        }
+
+        daysUntil := defaultReminderDays[0]
+        if params.DaysUntil != nil {
+            daysUntil = *params.DaysUntil
+        }
+        data = s.email.ReminderData(name, slug, previewToken, daysUntil)
+
+    case "memory-approved", "memory-rejected":
+        name, slug := previewFestivals[0].Name, previewFestivals[0].Slug
+        if params.Slug != "" {
+            festival, err := s.festivals.GetBySlug(ctx, params.Slug)
+            if err != nil {
+                return email.Message{}, err
+            }
+            name, slug = festival.Name, festival.Slug
+        }
+
+        var message string
+        if emailType == "memory-rejected" {
+            message = previewModeratorMessage
+        }
+        data = s.email.MemoryReviewData("Keisha", name, slug, previewMemoryID, previewToken, message)
+
+    default:
+        return email.Message{}, fmt.Errorf("%w: %q", ErrUnknownEmailType, emailType)
+    }
+
+    return email.Render(to, emailType, data)
+}
+
+// digestItems uses the festival given by slug, or the real upcoming
+// festivals for a live subscriber, or the fixtures.
+func (s *PreviewService) digestItems(ctx context.Context, params PreviewParams) ([]email.FestivalDigestItem, error) {
+    if params.Slug != "" {
+        festival, err := s.festivals.GetBySlug(ctx, params.Slug)
+        if err != nil {
+            return nil, err
+        }
+
+        dates, err := s.festivals.GetDates(ctx, festival.ID)
+        if err != nil {
+            return nil, err
+        }
+
+        item := email.FestivalDigestItem{
+            Name:     festival.Name,
+            Slug:     festival.Slug,
+            Date:     "Date to be announced",
+            Heritage: label(heritageLabels, festival.HeritageType),
+            Region:   label(regionLabels, festival.Region),
+        }
+        if d, ok := nextDate(dates); ok {
+            item.Date = formatDateRange(d.StartDate, d.EndDate)
+        }
+
+        return []email.FestivalDigestItem{item}, nil
+    }
+
+    if params.SubscriberID.Valid {
+        upcoming, err := s.queries.ListUpcomingFestivalDates(ctx)
+        if err != nil {
+            return nil, err
+        }
+
+        if len(upcoming) > 0 {
+            return digestItems(upcoming), nil
+        }
+    }
+
+    return previewFestivals, nil
+}
+
+// nextDate returns the earliest date that hasn't passed yet, or the most
+// recent one when they all have. dates are ordered newest year first.
+func nextDate(dates []db.FestivalDate) (db.FestivalDate, bool) {
+    if len(dates) == 0 {
+        return db.FestivalDate{}, false
+    }
+
+    today := time.Now().UTC().Truncate(24 * time.Hour)
+
+    next := dates[0]
+    for _, d := range dates[1:] {
+        if d.StartDate.Time.Before(today) {
+            break
+        }
+        next = d
+    }
+
+    return next, true
+}
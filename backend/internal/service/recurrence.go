@@ -0,0 +1,132 @@
+package service
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "log"
+    "time"
+
+    "github.com/aidantrabs/kultur/backend/internal/calendar"
+    "github.com/aidantrabs/kultur/backend/internal/db"
+    "github.com/google/uuid"
+    "github.com/jackc/pgx/v5"
+    "github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrInvalidRecurrence = errors.New("invalid recurrence rule")
+
+// RecurrenceYearsAhead is how many years past the current one get generated
+// festival dates.
+const RecurrenceYearsAhead = 3
+
+// SetRecurrence stores the festival's recurrence rule and generates its
+// upcoming dates. A nil rule removes the rule but keeps existing dates.
+func (s *FestivalService) SetRecurrence(ctx context.Context, id pgtype.UUID, rule *calendar.Rule) (db.Festival, error) {
+    var raw []byte
+    if rule != nil {
+        if err := rule.Validate(); err != nil {
+            return db.Festival{}, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+        }
+
+        var err error
+        raw, err = json.Marshal(rule)
+        if err != nil {
+            return db.Festival{}, err
+        }
+    }
+
+    festival, err := s.queries.UpdateFestivalRecurrence(ctx, db.UpdateFestivalRecurrenceParams{
+        ID:         id,
+        Recurrence: raw,
+    })
+    if errors.Is(err, pgx.ErrNoRows) {
+        return db.Festival{}, ErrFestivalNotFound
+    }
+    if err != nil {
+        return db.Festival{}, err
+    }
+
+    if rule != nil {
+        if _, err := s.generateDates(ctx, festival.ID, *rule); err != nil {
+            return db.Festival{}, err
+        }
+    }
+
+    return festival, nil
+}
+
+// GenerateDates fills in festival_dates for the current year and the next
+// RecurrenceYearsAhead years from every festival's recurrence rule. Dates are
+// tentative except Easter-relative ones, which are exact. Rows entered or
+// edited by admins, and confirmed rows, are left alone.
+func (s *FestivalService) GenerateDates(ctx context.Context) error {
+    festivals, err := s.queries.ListFestivalsWithRecurrence(ctx)
+    if err != nil {
+        return fmt.Errorf("failed to list festivals: %w", err)
+    }
+
+    var generated int64
+    var failed int
+    for _, f := range festivals {
+        var rule calendar.Rule
+        if err := json.Unmarshal(f.Recurrence, &rule); err != nil {
+            log.Printf("recurrence: festival %s: invalid rule: %v", uuid.UUID(f.ID.Bytes), err)
+            failed++
+            continue
+        }
+
+        n, err := s.generateDates(ctx, f.ID, rule)
+        if err != nil {
+            log.Printf("recurrence: festival %s: %v", uuid.UUID(f.ID.Bytes), err)
+            failed++
+            continue
+        }
+        generated += n
+    }
+
+    log.Printf("recurrence: generated %d festival dates", generated)
+
+    if failed > 0 {
+        return fmt.Errorf("failed to generate dates for %d festivals", failed)
+    }
+
+    return nil
+}
+
+func (s *FestivalService) generateDates(ctx context.Context, festivalID pgtype.UUID, rule calendar.Rule) (int64, error) {
+    year := time.Now().Year()
+
+    var generated int64
+    for y := year; y <= year+RecurrenceYearsAhead; y++ {
+        start, end, err := rule.Dates(y)
+        if errors.Is(err, calendar.ErrNoEstimate) {
+            continue
+        }
+        if err != nil {
+            return generated, err
+        }
+
+        params := db.UpsertGeneratedFestivalDateParams{
+            FestivalID: festivalID,
+            Year:       int32(y),
+            StartDate:  pgtype.Date{Time: start, Valid: true},
+            EndDate:    pgtype.Date{Time: end, Valid: !end.Equal(start)},
+        }
+
+        // the computus gives the exact date, as PrefillEasterDates writes it
+        var n int64
+        if rule.Type == calendar.RuleEaster {
+            n, err = s.queries.UpsertComputedFestivalDate(ctx, db.UpsertComputedFestivalDateParams(params))
+        } else {
+            n, err = s.queries.UpsertGeneratedFestivalDate(ctx, params)
+        }
+        if err != nil {
+            return generated, err
+        }
+        generated += n
+    }
+
+    return generated, nil
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "time"
+
+    "github.com/aidantrabs/kultur/backend/internal/db"
+    "github.com/aidantrabs/kultur/backend/internal/email"
+    "github.com/google/uuid"
+    "github.com/jackc/pgx/v5"
+    "github.com/jackc/pgx/v5/pgtype"
+)
+
+type ReminderService struct {
+    queries *db.Queries
+    email   *email.Service
+    loc     *time.Location
+}
+
+func NewReminderService(queries *db.Queries, emailSvc *email.Service, loc *time.Location) *ReminderService {
+    return &ReminderService{
+        queries: queries,
+        email:   emailSvc,
+        loc:     loc,
+    }
+}
+
+// SendDue sends every festival reminder that is due today. A reminder is
+// recorded in reminder_deliveries before it is sent, so each lead time is
+// used at most once per festival date.
+func (s *ReminderService) SendDue(ctx context.Context) error {
+    subs, err := s.queries.ListConfirmedWithReminders(ctx)
+    if err != nil {
+        return fmt.Errorf("failed to list reminder subscribers: %w", err)
+    }
+
+    if len(subs) == 0 {
+        return nil
+    }
+
+    now := time.Now().In(s.loc)
+    today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+    dates, err := s.queries.ListFestivalDatesBetween(ctx, db.ListFestivalDatesBetweenParams{
+        FromDate: pgtype.Date{Time: today, Valid: true},
+        ToDate:   pgtype.Date{Time: today.AddDate(0, 0, MaxReminderDays), Valid: true},
+    })
+    if err != nil {
+        return fmt.Errorf("failed to list festival dates: %w", err)
+    }
+
+    bySlug := make(map[string][]db.ListFestivalDatesBetweenRow)
+    for _, d := range dates {
+        bySlug[d.Slug] = append(bySlug[d.Slug], d)
+    }
+
+    var sent, failed int
+    for _, sub := range subs {
+        reminders, err := parseReminders(sub.FestivalReminders)
+        if err != nil {
+            log.Printf("reminders: subscription %s: %v", uuid.UUID(sub.ID.Bytes), err)
+            continue
+        }
+
+        for _, r := range reminders {
+            for _, d := range bySlug[r.Slug] {
+                daysUntil := int(d.StartDate.Time.Sub(today).Hours() / 24)
+
+                lead, ok := leadTimeFor(r.DaysBefore, daysUntil)
+                if !ok {
+                    continue
+                }
+
+                delivered, err := s.send(ctx, sub, d, lead, daysUntil)
+                if err != nil {
+                    log.Printf("reminders: failed to send %s to subscription %s: %v", d.Slug, uuid.UUID(sub.ID.Bytes), err)
+                    failed++
+                    continue
+                }
+
+                if delivered {
+                    sent++
+                }
+            }
+        }
+    }
+
+    log.Printf("reminders: sent %d festival reminders", sent)
+
+    if failed > 0 {
+        return fmt.Errorf("failed to send %d festival reminders", failed)
+    }
+
+    return nil
+}
+
+func (s *ReminderService) send(ctx context.Context, sub db.Subscription, date db.ListFestivalDatesBetweenRow, lead, daysUntil int) (bool, error) {
+    delivery, err := s.queries.CreateReminderDelivery(ctx, db.CreateReminderDeliveryParams{
+        SubscriptionID: sub.ID,
+        FestivalDateID: date.ID,
+        DaysBefore:     int32(lead),
+    })
+    if errors.Is(err, pgx.ErrNoRows) {
+        return false, nil // already sent
+    }
+    if err != nil {
+        return false, err
+    }
+
+    if err := s.email.SendFestivalReminder(ctx, sub.Email, date.Name, date.Slug, sub.UnsubscribeToken, daysUntil); err != nil {
+        // release the delivery so tomorrow's run can retry it
+        if delErr := s.queries.DeleteReminderDelivery(ctx, delivery.ID); delErr != nil {
+            log.Printf("reminders: failed to release delivery %s: %v", uuid.UUID(delivery.ID.Bytes), delErr)
+        }
+        return false, err
+    }
+
+    return true, nil
+}
+
+// leadTimeFor picks the shortest lead time that has been reached, so a
+// missed day still sends the reminder once instead of skipping it.
+func leadTimeFor(days []int, daysUntil int) (int, bool) {
+    lead, found := 0, false
+    for _, d := range days {
+        if d >= daysUntil && (!found || d < lead) {
+            lead, found = d, true
+        }
+    }
+
+    return lead, found
+}
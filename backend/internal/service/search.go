@@ -0,0 +1,135 @@
+package service
+
+import (
+    "context"
+    "errors"
+    "html"
+    "regexp"
+    "strings"
+    "unicode"
+
+    "github.com/aidantrabs/kultur/backend/internal/db"
+    "golang.org/x/text/unicode/norm"
+)
+
+var ErrInvalidQuery = errors.New("invalid search query")
+
+const (
+    maxSearchTerms   = 8
+    maxSearchResults = 20
+)
+
+// spellings that should find each other, matched after accents are removed
+var searchSynonyms = [][]string{
+    {"divali", "diwali", "deepavali", "deepawali"},
+    {"phagwa", "holi"},
+    {"hosay", "hussay", "hosein", "muharram"},
+    {"mas", "masquerade"},
+    {"pan", "steelpan", "steelband"},
+    {"jouvert", "jouvay"},
+}
+
+var synonymIndex = func() map[string][]string {
+    index := make(map[string][]string)
+    for _, group := range searchSynonyms {
+        for _, word := range group {
+            index[word] = group
+        }
+    }
+    return index
+}()
+
+var searchTermPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+type SearchService struct {
+    queries *db.Queries
+}
+
+func NewSearchService(queries *db.Queries) *SearchService {
+    return &SearchService{queries: queries}
+}
+
+type SearchResults struct {
+    Query     string
+    Festivals []db.SearchFestivalsRow
+    Memories  []db.SearchMemoriesRow
+}
+
+// Search finds published festivals and approved memories matching q, best
+// match first. Snippets are HTML with matches wrapped in <mark>.
+func (s *SearchService) Search(ctx context.Context, q string) (SearchResults, error) {
+    query := buildTSQuery(q)
+    if query == "" {
+        return SearchResults{}, ErrInvalidQuery
+    }
+
+    festivals, err := s.queries.SearchFestivals(ctx, db.SearchFestivalsParams{
+        Query:      query,
+        MaxResults: maxSearchResults,
+    })
+    if err != nil {
+        return SearchResults{}, err
+    }
+
+    memories, err := s.queries.SearchMemories(ctx, db.SearchMemoriesParams{
+        Query:      query,
+        MaxResults: maxSearchResults,
+    })
+    if err != nil {
+        return SearchResults{}, err
+    }
+
+    for i := range festivals {
+        festivals[i].Snippet = escapeSnippet(festivals[i].Snippet)
+    }
+    for i := range memories {
+        memories[i].Snippet = escapeSnippet(memories[i].Snippet)
+    }
+
+    return SearchResults{
+        Query:     q,
+        Festivals: festivals,
+        Memories:  memories,
+    }, nil
+}
+
+// buildTSQuery turns free text into a to_tsquery expression. Every term must
+// match, either as a prefix or as one of its synonyms, e.g. "diwali lights"
+// becomes "(divali | diwali | deepavali | deepawali) & lights:*".
+func buildTSQuery(q string) string {
+    terms := searchTermPattern.FindAllString(strings.ToLower(removeAccents(q)), -1)
+    if len(terms) > maxSearchTerms {
+        terms = terms[:maxSearchTerms]
+    }
+
+    parts := make([]string, 0, len(terms))
+    for _, term := range terms {
+        group, ok := synonymIndex[term]
+        if !ok {
+            parts = append(parts, term+":*")
+            continue
+        }
+
+        parts = append(parts, "("+strings.Join(group, " | ")+")")
+    }
+
+    return strings.Join(parts, " & ")
+}
+
+func removeAccents(s string) string {
+    var b strings.Builder
+    for _, r := range norm.NFD.String(s) {
+        if !unicode.Is(unicode.Mn, r) {
+            b.WriteRune(r)
+        }
+    }
+    return b.String()
+}
+
+// escapeSnippet escapes ts_headline output, which is raw column text, and
+// keeps only the <mark> tags it added.
+func escapeSnippet(snippet string) string {
+    escaped := html.EscapeString(snippet)
+    escaped = strings.ReplaceAll(escaped, "&lt;mark&gt;", "<mark>")
+    return strings.ReplaceAll(escaped, "&lt;/mark&gt;", "</mark>")
+}
@@ -4,7 +4,14 @@ import (
     "context"
     "crypto/rand"
     "encoding/hex"
+    "encoding/json"
     "errors"
+    "fmt"
+    "log"
+    "net/mail"
+    "slices"
+    "strings"
+    "time"
 
     "github.com/aidantrabs/kultur/backend/internal/db"
     "github.com/aidantrabs/kultur/backend/internal/email"
@@ -15,29 +22,61 @@ import (
 var (
     ErrSubscriptionNotFound = errors.New("subscription not found")
     ErrInvalidToken         = errors.New("invalid token")
+    ErrTokenExpired         = errors.New("token expired")
     ErrEmailAlreadyExists   = errors.New("email already subscribed")
+    ErrInvalidReminders     = errors.New("invalid festival reminders")
+    ErrInvalidEmail         = errors.New("invalid email address")
 )
 
+// MaxReminderDays is the longest lead time a festival reminder can use.
+const MaxReminderDays = 90
+
+// lead times used when a reminder doesn't specify any
+var defaultReminderDays = []int{7, 1}
+
+// FestivalReminder is one entry of the festival_reminders column.
+type FestivalReminder struct {
+    Slug       string `json:"slug"`
+    DaysBefore []int  `json:"days_before"`
+}
+
 type SubscriptionService struct {
-    queries *db.Queries
-    email   *email.Service
+    queries         *db.Queries
+    festivals       *FestivalService
+    email           *email.Service
+    confirmationTTL time.Duration
 }
 
-func NewSubscriptionService(queries *db.Queries, emailSvc *email.Service) *SubscriptionService {
+func NewSubscriptionService(queries *db.Queries, festivalService *FestivalService, emailSvc *email.Service, confirmationTTL time.Duration) *SubscriptionService {
     return &SubscriptionService{
-        queries: queries,
-        email:   emailSvc,
+        queries:         queries,
+        festivals:       festivalService,
+        email:           emailSvc,
+        confirmationTTL: confirmationTTL,
     }
 }
 
 type CreateSubscriptionParams struct {
-    Email        string
-    DigestWeekly bool
+    Email             string
+    DigestWeekly      bool
+    FestivalReminders []FestivalReminder
 }
 
 func (s *SubscriptionService) Create(ctx context.Context, params CreateSubscriptionParams) (db.Subscription, error) {
+    address, err := parseEmail(params.Email)
+    if err != nil {
+        return db.Subscription{}, err
+    }
+    params.Email = address
+
     // Check if email already exists
     existing, err := s.queries.GetSubscriptionByEmail(ctx, params.Email)
+    if err == nil && !existing.Confirmed.Bool {
+        // Still waiting on confirmation, send a fresh link. The choices from
+        // the first signup stay as they are: anyone can submit this address,
+        // so new choices would take effect without the owner's say.
+        return s.resendConfirmation(ctx, existing)
+    }
     if err == nil {
         // Email already exists, return the existing subscription
         return existing, ErrEmailAlreadyExists
@@ -46,6 +85,16 @@ func (s *SubscriptionService) Create(ctx context.Context, params CreateSubscript
         return db.Subscription{}, err
     }
 
+    reminders, err := s.normalizeReminders(ctx, params.FestivalReminders)
+    if err != nil {
+        return db.Subscription{}, err
+    }
+
+    remindersJSON, err := json.Marshal(reminders)
+    if err != nil {
+        return db.Subscription{}, err
+    }
+
     confirmToken, err := generateToken()
     if err != nil {
         return db.Subscription{}, err
@@ -56,28 +105,58 @@ func (s *SubscriptionService) Create(ctx context.Context, params CreateSubscript
         return db.Subscription{}, err
     }
 
+    calendarToken, err := generateToken()
+    if err != nil {
+        return db.Subscription{}, err
+    }
+
     sub, err := s.queries.CreateSubscription(ctx, db.CreateSubscriptionParams{
-        Email:             params.Email,
-        DigestWeekly:      pgtype.Bool{Bool: params.DigestWeekly, Valid: true},
-        FestivalReminders: []byte("[]"),
-        ConfirmationToken: pgtype.Text{String: confirmToken, Valid: true},
-        UnsubscribeToken:  unsubToken,
+        Email:                 params.Email,
+        DigestWeekly:          pgtype.Bool{Bool: params.DigestWeekly, Valid: true},
+        FestivalReminders:     remindersJSON,
+        ConfirmationToken:     pgtype.Text{String: confirmToken, Valid: true},
+        UnsubscribeToken:      unsubToken,
+        ConfirmationExpiresAt: s.confirmationExpiry(),
+        CalendarToken:         calendarToken,
     })
     if err != nil {
         return db.Subscription{}, err
     }
 
-    if err := s.queries.ConfirmSubscription(ctx, sub.ID); err != nil {
-        // log error but don't fail the request
+    if err := s.email.SendConfirmation(ctx, params.Email, confirmToken, s.confirmationTTL); err != nil {
+        // log error but don't fail the request, subscribing again resends the link
+        log.Printf("subscriptions: failed to send confirmation: %v", err)
     }
 
-    if err := s.email.SendWelcome(params.Email, unsubToken); err != nil {
-        // log error but don't fail the request
+    return sub, nil
+}
+
+func (s *SubscriptionService) resendConfirmation(ctx context.Context, sub db.Subscription) (db.Subscription, error) {
+    confirmToken, err := generateToken()
+    if err != nil {
+        return db.Subscription{}, err
+    }
+
+    sub, err = s.queries.RefreshConfirmationToken(ctx, db.RefreshConfirmationTokenParams{
+        ID:                    sub.ID,
+        ConfirmationToken:     pgtype.Text{String: confirmToken, Valid: true},
+        ConfirmationExpiresAt: s.confirmationExpiry(),
+    })
+    if err != nil {
+        return db.Subscription{}, err
+    }
+
+    if err := s.email.SendConfirmation(ctx, sub.Email, confirmToken, s.confirmationTTL); err != nil {
+        log.Printf("subscriptions: failed to resend confirmation: %v", err)
     }
 
     return sub, nil
 }
 
+func (s *SubscriptionService) confirmationExpiry() pgtype.Timestamptz {
+    return pgtype.Timestamptz{Time: time.Now().Add(s.confirmationTTL), Valid: true}
+}
+
 func (s *SubscriptionService) Confirm(ctx context.Context, token string) error {
     sub, err := s.queries.GetSubscriptionByConfirmationToken(ctx, pgtype.Text{String: token, Valid: true})
     if errors.Is(err, pgx.ErrNoRows) {
@@ -87,14 +166,32 @@ func (s *SubscriptionService) Confirm(ctx context.Context, token string) error {
         return err
     }
 
+    if sub.ConfirmationExpiresAt.Valid && time.Now().After(sub.ConfirmationExpiresAt.Time) {
+        return ErrTokenExpired
+    }
+
     if err := s.queries.ConfirmSubscription(ctx, sub.ID); err != nil {
         return err
     }
 
-    if err := s.email.SendWelcome(sub.Email, sub.UnsubscribeToken); err != nil {
+    if err := s.email.SendWelcome(ctx, sub.Email, sub.UnsubscribeToken); err != nil {
         // log error but don't fail the request
+        log.Printf("subscriptions: failed to send welcome email: %v", err)
+    }
+
+    return nil
+}
+
+// DeleteExpiredUnconfirmed removes subscriptions whose confirmation link
+// expired before it was used.
+func (s *SubscriptionService) DeleteExpiredUnconfirmed(ctx context.Context) error {
+    deleted, err := s.queries.DeleteExpiredUnconfirmedSubscriptions(ctx)
+    if err != nil {
+        return fmt.Errorf("failed to delete expired subscriptions: %w", err)
     }
 
+    log.Printf("subscriptions: removed %d expired unconfirmed subscriptions", deleted)
+
     return nil
 }
 
@@ -110,14 +207,219 @@ func (s *SubscriptionService) Unsubscribe(ctx context.Context, token string) err
     return s.queries.DeleteSubscription(ctx, sub.ID)
 }
 
-func (s *SubscriptionService) ListAll(ctx context.Context) ([]db.Subscription, error) {
-    return s.queries.ListAllSubscriptions(ctx)
+func (s *SubscriptionService) GetReminders(ctx context.Context, token string) ([]FestivalReminder, error) {
+    sub, err := s.queries.GetSubscriptionByUnsubscribeToken(ctx, token)
+    if errors.Is(err, pgx.ErrNoRows) {
+        return nil, ErrInvalidToken
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    return parseReminders(sub.FestivalReminders)
+}
+
+func (s *SubscriptionService) UpdateReminders(ctx context.Context, token string, reminders []FestivalReminder) ([]FestivalReminder, error) {
+    sub, err := s.queries.GetSubscriptionByUnsubscribeToken(ctx, token)
+    if errors.Is(err, pgx.ErrNoRows) {
+        return nil, ErrInvalidToken
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    reminders, err = s.normalizeReminders(ctx, reminders)
+    if err != nil {
+        return nil, err
+    }
+
+    remindersJSON, err := json.Marshal(reminders)
+    if err != nil {
+        return nil, err
+    }
+
+    if _, err := s.queries.UpdateSubscriptionReminders(ctx, db.UpdateSubscriptionRemindersParams{
+        ID:                sub.ID,
+        FestivalReminders: remindersJSON,
+    }); err != nil {
+        return nil, err
+    }
+
+    return reminders, nil
+}
+
+// GetCalendarToken returns the token for the subscriber's personal calendar
+// feed. Like the reminder endpoints, token is the unsubscribe token.
+func (s *SubscriptionService) GetCalendarToken(ctx context.Context, token string) (string, error) {
+    sub, err := s.queries.GetSubscriptionByUnsubscribeToken(ctx, token)
+    if errors.Is(err, pgx.ErrNoRows) {
+        return "", ErrInvalidToken
+    }
+    if err != nil {
+        return "", err
+    }
+
+    return sub.CalendarToken, nil
+}
+
+// RotateCalendarToken replaces the calendar feed token, so the old feed URL
+// stops working.
+func (s *SubscriptionService) RotateCalendarToken(ctx context.Context, token string) (string, error) {
+    sub, err := s.queries.GetSubscriptionByUnsubscribeToken(ctx, token)
+    if errors.Is(err, pgx.ErrNoRows) {
+        return "", ErrInvalidToken
+    }
+    if err != nil {
+        return "", err
+    }
+
+    calendarToken, err := generateToken()
+    if err != nil {
+        return "", err
+    }
+
+    sub, err = s.queries.UpdateCalendarToken(ctx, db.UpdateCalendarTokenParams{
+        ID:            sub.ID,
+        CalendarToken: calendarToken,
+    })
+    if err != nil {
+        return "", err
+    }
+
+    return sub.CalendarToken, nil
+}
+
+// normalizeReminders checks every slug against the published festivals and
+// cleans up the lead times (defaulted, deduplicated, longest first).
+func (s *SubscriptionService) normalizeReminders(ctx context.Context, reminders []FestivalReminder) ([]FestivalReminder, error) {
+    normalized := make([]FestivalReminder, 0, len(reminders))
+    seen := make(map[string]bool, len(reminders))
+
+    for _, r := range reminders {
+        if r.Slug == "" {
+            return nil, fmt.Errorf("%w: slug is required", ErrInvalidReminders)
+        }
+
+        if seen[r.Slug] {
+            return nil, fmt.Errorf("%w: duplicate festival %q", ErrInvalidReminders, r.Slug)
+        }
+        seen[r.Slug] = true
+
+        if _, err := s.festivals.GetBySlug(ctx, r.Slug); err != nil {
+            if errors.Is(err, ErrFestivalNotFound) {
+                return nil, fmt.Errorf("%w: unknown festival %q", ErrInvalidReminders, r.Slug)
+            }
+            return nil, err
+        }
+
+        days := r.DaysBefore
+        if len(days) == 0 {
+            days = defaultReminderDays
+        }
+
+        for _, d := range days {
+            if d < 0 || d > MaxReminderDays {
+                return nil, fmt.Errorf("%w: days_before must be between 0 and %d", ErrInvalidReminders, MaxReminderDays)
+            }
+        }
+
+        days = slices.Clone(days)
+        slices.Sort(days)
+        days = slices.Compact(days)
+        slices.Reverse(days)
+
+        normalized = append(normalized, FestivalReminder{Slug: r.Slug, DaysBefore: days})
+    }
+
+    return normalized, nil
+}
+
+func parseReminders(raw []byte) ([]FestivalReminder, error) {
+    reminders := []FestivalReminder{}
+    if len(raw) == 0 {
+        return reminders, nil
+    }
+
+    if err := json.Unmarshal(raw, &reminders); err != nil {
+        return nil, fmt.Errorf("failed to parse festival reminders: %w", err)
+    }
+
+    return reminders, nil
+}
+
+type ListSubscriptionsParams struct {
+    Confirmed    *bool
+    DigestWeekly *bool
+    From         *time.Time
+    To           *time.Time
+    Cursor       string
+    Limit        int
+}
+
+// List returns one page of subscriptions, newest first, with the total
+// number matching the filters.
+func (s *SubscriptionService) List(ctx context.Context, params ListSubscriptionsParams) (Page[db.Subscription], error) {
+    from, to, err := dateRange(params.From, params.To)
+    if err != nil {
+        return Page[db.Subscription]{}, err
+    }
+
+    var after timeCursor
+    if err := after.decode(params.Cursor); err != nil {
+        return Page[db.Subscription]{}, err
+    }
+
+    size := pageSize(params.Limit)
+
+    rows, err := s.queries.ListSubscriptionsPage(ctx, db.ListSubscriptionsPageParams{
+        Confirmed:      optionalBool(params.Confirmed),
+        DigestWeekly:   optionalBool(params.DigestWeekly),
+        CreatedFrom:    from,
+        CreatedTo:      to,
+        AfterID:        after.ID,
+        AfterCreatedAt: after.At,
+        MaxResults:     int32(size + 1),
+    })
+    if err != nil {
+        return Page[db.Subscription]{}, err
+    }
+
+    total, err := s.queries.CountSubscriptions(ctx, db.CountSubscriptionsParams{
+        Confirmed:    optionalBool(params.Confirmed),
+        DigestWeekly: optionalBool(params.DigestWeekly),
+        CreatedFrom:  from,
+        CreatedTo:    to,
+    })
+    if err != nil {
+        return Page[db.Subscription]{}, err
+    }
+
+    page, err := newPage(rows, size, func(last db.Subscription) (string, error) {
+        return encodeCursor(timeCursor{At: last.CreatedAt, ID: last.ID})
+    })
+    page.Total = &total
+
+    return page, err
 }
 
 func (s *SubscriptionService) Delete(ctx context.Context, id pgtype.UUID) error {
     return s.queries.DeleteSubscription(ctx, id)
 }
 
+// parseEmail checks raw is a single bare address, such as "jo@example.com",
+// and returns it trimmed. It keeps names, CR/LF and lists out of the To
+// header of the emails sent to it.
+func parseEmail(raw string) (string, error) {
+    raw = strings.TrimSpace(raw)
+
+    addr, err := mail.ParseAddress(raw)
+    if err != nil || addr.Name != "" || addr.Address != raw {
+        return "", ErrInvalidEmail
+    }
+
+    return addr.Address, nil
+}
+
 func generateToken() (string, error) {
     bytes := make([]byte, 32)
     if _, err := rand.Read(bytes); err != nil {
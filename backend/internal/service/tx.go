@@ -0,0 +1,24 @@
+package service
+
+import (
+    "context"
+
+    "github.com/aidantrabs/kultur/backend/internal/db"
+    "github.com/jackc/pgx/v5/pgxpool"
+)
+
+// inTx runs fn with queries bound to a new transaction, committing if fn
+// succeeds and rolling back otherwise.
+func inTx(ctx context.Context, pool *pgxpool.Pool, queries *db.Queries, fn func(q *db.Queries) error) error {
+    tx, err := pool.Begin(ctx)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback(ctx)
+
+    if err := fn(queries.WithTx(tx)); err != nil {
+        return err
+    }
+
+    return tx.Commit(ctx)
+}
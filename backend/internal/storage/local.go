@@ -0,0 +1,125 @@
+package storage
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "io/fs"
+    "net/http"
+    "net/url"
+    "os"
+    "path/filepath"
+    "strings"
+)
+
+// Local stores files under dir. The server serves them itself, see Handler.
+type Local struct {
+    dir       string
+    publicURL string
+    mountPath string
+}
+
+// NewLocal serves files at the path of publicURL, so the public URL has to
+// reach this server with its path unchanged, directly or through a proxy.
+func NewLocal(dir, publicURL string) (*Local, error) {
+    if dir == "" {
+        dir = "tmp/media"
+    }
+
+    publicURL = strings.TrimSuffix(publicURL, "/")
+
+    u, err := url.Parse(publicURL)
+    if err != nil {
+        return nil, fmt.Errorf("invalid media public URL: %w", err)
+    }
+
+    mountPath := strings.TrimSuffix(u.Path, "/")
+    if mountPath == "" || mountPath == "/api" || strings.HasPrefix(mountPath, "/api/") {
+        return nil, fmt.Errorf("media public URL %q needs a path outside /api to serve local files under, such as /media", publicURL)
+    }
+
+    if err := os.MkdirAll(dir, 0o755); err != nil {
+        return nil, fmt.Errorf("failed to create media directory: %w", err)
+    }
+
+    return &Local{dir: dir, publicURL: publicURL, mountPath: mountPath}, nil
+}
+
+// Put writes to a temporary file first, so a file is never served half
+// written.
+func (l *Local) Put(ctx context.Context, key string, data []byte, contentType string) error {
+    if err := checkKey(key); err != nil {
+        return err
+    }
+
+    path := filepath.Join(l.dir, filepath.FromSlash(key))
+    if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+        return err
+    }
+
+    tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+    if err != nil {
+        return err
+    }
+    defer os.Remove(tmp.Name())
+
+    if _, err := tmp.Write(data); err != nil {
+        tmp.Close()
+        return err
+    }
+    if err := tmp.Close(); err != nil {
+        return err
+    }
+
+    if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+        return err
+    }
+
+    return os.Rename(tmp.Name(), path)
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+    if err := checkKey(key); err != nil {
+        return err
+    }
+
+    err := os.Remove(filepath.Join(l.dir, filepath.FromSlash(key)))
+    if errors.Is(err, fs.ErrNotExist) {
+        return nil
+    }
+
+    return err
+}
+
+func (l *Local) URL(key string) string {
+    return l.publicURL + "/" + key
+}
+
+// MountPath is the path of the public URL, where Handler has to be mounted.
+func (l *Local) MountPath() string {
+    return l.mountPath
+}
+
+// Handler serves the stored files with long-lived cache headers. Mount it at
+// MountPath with that prefix stripped. Directories are not listed.
+func (l *Local) Handler() http.Handler {
+    files := http.FileServer(http.Dir(l.dir))
+
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        key := strings.TrimPrefix(r.URL.Path, "/")
+        if checkKey(key) != nil || strings.HasPrefix(filepath.Base(key), ".") {
+            http.NotFound(w, r)
+            return
+        }
+
+        info, err := os.Stat(filepath.Join(l.dir, filepath.FromSlash(key)))
+        if err != nil || info.IsDir() {
+            http.NotFound(w, r)
+            return
+        }
+
+        w.Header().Set("Cache-Control", CacheControl)
+        w.Header().Set("X-Content-Type-Options", "nosniff")
+        files.ServeHTTP(w, r)
+    })
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+    "bytes"
+    "context"
+    "fmt"
+    "strings"
+
+    "github.com/minio/minio-go/v7"
+    "github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3 stores files in a bucket on S3 or any S3-compatible service such as
+// MinIO. The bucket (or a CDN in front of it) serves the files, so it must
+// allow public reads.
+type S3 struct {
+    client    *minio.Client
+    bucket    string
+    publicURL string
+}
+
+func NewS3(cfg Config) (*S3, error) {
+    client, err := minio.New(cfg.S3Endpoint, &minio.Options{
+        Creds:  credentials.NewStaticV4(cfg.S3AccessKeyID, cfg.S3SecretAccessKey, ""),
+        Secure: cfg.S3UseSSL,
+        Region: cfg.S3Region,
+    })
+    if err != nil {
+        return nil, fmt.Errorf("failed to create S3 client: %w", err)
+    }
+
+    publicURL := cfg.PublicURL
+    if publicURL == "" {
+        scheme := "http"
+        if cfg.S3UseSSL {
+            scheme = "https"
+        }
+        publicURL = fmt.Sprintf("%s://%s/%s", scheme, cfg.S3Endpoint, cfg.S3Bucket)
+    }
+
+    return &S3{client: client, bucket: cfg.S3Bucket, publicURL: strings.TrimSuffix(publicURL, "/")}, nil
+}
+
+func (s *S3) Put(ctx context.Context, key string, data []byte, contentType string) error {
+    if err := checkKey(key); err != nil {
+        return err
+    }
+
+    _, err := s.client.PutObject(ctx, s.bucket, key, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{
+        ContentType:  contentType,
+        CacheControl: CacheControl,
+    })
+
+    return err
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+    if err := checkKey(key); err != nil {
+        return err
+    }
+
+    return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (s *S3) URL(key string) string {
+    return s.publicURL + "/" + key
+}
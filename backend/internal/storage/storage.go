@@ -0,0 +1,80 @@
+// Package storage stores uploaded media files, either on the local
+// filesystem or in an S3-compatible bucket.
+package storage
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "strings"
+)
+
+// Storage saves files under a key such as "<asset id>/800w.webp" and knows
+// the public URL each key is served from.
+type Storage interface {
+    Put(ctx context.Context, key string, data []byte, contentType string) error
+    Delete(ctx context.Context, key string) error
+    URL(key string) string
+}
+
+const (
+    BackendLocal = "local"
+    BackendS3    = "s3"
+)
+
+// CacheControl is sent with every stored file. Keys are never reused, so
+// files can be cached for good.
+const CacheControl = "public, max-age=31536000, immutable"
+
+var ErrInvalidKey = errors.New("invalid storage key")
+
+type Config struct {
+    Backend string
+    // PublicURL is the URL keys are appended to. It defaults to BaseURL/media
+    // for local storage and to the bucket's URL for S3.
+    PublicURL string
+    BaseURL   string
+
+    LocalDir string
+
+    S3Endpoint        string
+    S3Bucket          string
+    S3Region          string
+    S3AccessKeyID     string
+    S3SecretAccessKey string
+    S3UseSSL          bool
+}
+
+// New builds the Storage for cfg.Backend, local when it is empty.
+func New(cfg Config) (Storage, error) {
+    switch cfg.Backend {
+    case "", BackendLocal:
+        publicURL := cfg.PublicURL
+        if publicURL == "" {
+            publicURL = strings.TrimSuffix(cfg.BaseURL, "/") + "/media"
+        }
+        return NewLocal(cfg.LocalDir, publicURL)
+    case BackendS3:
+        if cfg.S3Endpoint == "" || cfg.S3Bucket == "" {
+            return nil, fmt.Errorf("S3_ENDPOINT and S3_BUCKET are required for the %s backend", cfg.Backend)
+        }
+        return NewS3(cfg)
+    default:
+        return nil, fmt.Errorf("unknown media storage backend %q", cfg.Backend)
+    }
+}
+
+// checkKey rejects keys that could escape the storage root.
+func checkKey(key string) error {
+    if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+        return ErrInvalidKey
+    }
+
+    for _, part := range strings.Split(key, "/") {
+        if part == "" || part == "." || part == ".." {
+            return ErrInvalidKey
+        }
+    }
+
+    return nil
+}
@@ -0,0 +1,163 @@
+package storage
+
+import (
+    "context"
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+func TestCheckKey(t *testing.T) {
+    tests := []struct {
+        key string
+        ok  bool
+    }{
+        {"0b6f3a52-5c1e-4c83-9d3f-2f5d1c0a7e11/800w.webp", true},
+        {"asset/original.jpg", true},
+        {"a", true},
+        {"..a/b", true},
+        {"", false},
+        {"/etc/passwd", false},
+        {"../secret", false},
+        {"asset/../../secret", false},
+        {"asset/..", false},
+        {"./asset", false},
+        {"asset//800w.webp", false},
+        {"asset/", false},
+        {`asset\..\..\secret`, false},
+        {`..\secret`, false},
+    }
+
+    for _, tt := range tests {
+        err := checkKey(tt.key)
+        if tt.ok && err != nil {
+            t.Errorf("checkKey(%q) = %v, want nil", tt.key, err)
+        }
+        if !tt.ok && !errors.Is(err, ErrInvalidKey) {
+            t.Errorf("checkKey(%q) = %v, want ErrInvalidKey", tt.key, err)
+        }
+    }
+}
+
+func TestLocalRejectsInvalidKeys(t *testing.T) {
+    root := t.TempDir()
+    dir := filepath.Join(root, "media")
+
+    local, err := NewLocal(dir, "https://kultur.example/media")
+    if err != nil {
+        t.Fatalf("NewLocal: %v", err)
+    }
+
+    ctx := context.Background()
+    if err := local.Put(ctx, "../escaped.txt", []byte("x"), "text/plain"); !errors.Is(err, ErrInvalidKey) {
+        t.Errorf("Put outside the root = %v, want ErrInvalidKey", err)
+    }
+    if _, err := os.Stat(filepath.Join(root, "escaped.txt")); err == nil {
+        t.Error("Put wrote a file outside the media directory")
+    }
+
+    if err := os.WriteFile(filepath.Join(root, "keep.txt"), []byte("x"), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    if err := local.Delete(ctx, "../keep.txt"); !errors.Is(err, ErrInvalidKey) {
+        t.Errorf("Delete outside the root = %v, want ErrInvalidKey", err)
+    }
+    if _, err := os.Stat(filepath.Join(root, "keep.txt")); err != nil {
+        t.Error("Delete removed a file outside the media directory")
+    }
+}
+
+func TestLocalHandler(t *testing.T) {
+    local, err := NewLocal(t.TempDir(), "https://kultur.example/media")
+    if err != nil {
+        t.Fatalf("NewLocal: %v", err)
+    }
+
+    if err := local.Put(context.Background(), "asset/800w.webp", []byte("webp"), "image/webp"); err != nil {
+        t.Fatalf("Put: %v", err)
+    }
+
+    tests := []struct {
+        path string
+        want int
+    }{
+        {"/asset/800w.webp", http.StatusOK},
+        {"/asset/missing.webp", http.StatusNotFound},
+        {"/asset", http.StatusNotFound},
+        {"/asset/", http.StatusNotFound},
+        {"/../asset/800w.webp", http.StatusNotFound},
+        {"/asset/.upload-123", http.StatusNotFound},
+    }
+
+    for _, tt := range tests {
+        rec := httptest.NewRecorder()
+        req := httptest.NewRequest(http.MethodGet, "/", nil)
+        req.URL.Path = tt.path
+
+        local.Handler().ServeHTTP(rec, req)
+        if rec.Code != tt.want {
+            t.Errorf("GET %s = %d, want %d", tt.path, rec.Code, tt.want)
+        }
+        if tt.want == http.StatusOK && rec.Header().Get("Cache-Control") != CacheControl {
+            t.Errorf("GET %s Cache-Control = %q", tt.path, rec.Header().Get("Cache-Control"))
+        }
+    }
+}
+
+func TestLocalMountPath(t *testing.T) {
+    tests := []struct {
+        publicURL string
+        wantMount string
+        wantURL   string
+        wantErr   bool
+    }{
+        {"https://kultur.example/media", "/media", "https://kultur.example/media/a/800w.webp", false},
+        {"https://kultur.example/media/", "/media", "https://kultur.example/media/a/800w.webp", false},
+        {"https://cdn.kultur.example/uploads/festivals", "/uploads/festivals", "https://cdn.kultur.example/uploads/festivals/a/800w.webp", false},
+        {"/media", "/media", "/media/a/800w.webp", false},
+        {"https://cdn.kultur.example", "", "", true},
+        {"https://cdn.kultur.example/", "", "", true},
+        {"https://kultur.example/api", "", "", true},
+        {"https://kultur.example/api/media", "", "", true},
+        {"://bad", "", "", true},
+    }
+
+    for _, tt := range tests {
+        local, err := NewLocal(t.TempDir(), tt.publicURL)
+        if tt.wantErr {
+            if err == nil {
+                t.Errorf("NewLocal(%q) succeeded, want error", tt.publicURL)
+            }
+            continue
+        }
+
+        if err != nil {
+            t.Errorf("NewLocal(%q): %v", tt.publicURL, err)
+            continue
+        }
+        if got := local.MountPath(); got != tt.wantMount {
+            t.Errorf("NewLocal(%q).MountPath() = %q, want %q", tt.publicURL, got, tt.wantMount)
+        }
+        if got := local.URL("a/800w.webp"); got != tt.wantURL {
+            t.Errorf("NewLocal(%q).URL = %q, want %q", tt.publicURL, got, tt.wantURL)
+        }
+    }
+}
+
+func TestNewDefaultsToBaseURLMedia(t *testing.T) {
+    store, err := New(Config{BaseURL: "https://kultur.example/", LocalDir: t.TempDir()})
+    if err != nil {
+        t.Fatalf("New: %v", err)
+    }
+
+    local, ok := store.(*Local)
+    if !ok {
+        t.Fatalf("New returned %T, want *Local", store)
+    }
+    if local.MountPath() != "/media" {
+        t.Errorf("MountPath() = %q, want /media", local.MountPath())
+    }
+}
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE job_runs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    job_name VARCHAR(100) NOT NULL,
+    period_key VARCHAR(50) NOT NULL,
+    started_at TIMESTAMPTZ DEFAULT NOW(),
+    finished_at TIMESTAMPTZ,
+    error TEXT,
+    UNIQUE(job_name, period_key)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS job_runs;
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE reminder_deliveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    festival_date_id UUID NOT NULL REFERENCES festival_dates(id) ON DELETE CASCADE,
+    days_before INTEGER NOT NULL,
+    sent_at TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE(subscription_id, festival_date_id, days_before)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS reminder_deliveries;
@@ -0,0 +1,10 @@
+-- +goose Up
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS confirmation_expires_at TIMESTAMPTZ;
+
+-- links sent before expiry was tracked promised 24 hours
+UPDATE subscriptions
+SET confirmation_expires_at = created_at + INTERVAL '24 hours'
+WHERE confirmed = false AND confirmation_expires_at IS NULL;
+
+-- +goose Down
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS confirmation_expires_at;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE email_outbox ADD COLUMN IF NOT EXISTS text_body TEXT;
+ALTER TABLE email_outbox ADD COLUMN IF NOT EXISTS headers JSONB DEFAULT '{}';
+
+-- +goose Down
+ALTER TABLE email_outbox DROP COLUMN IF EXISTS text_body;
+ALTER TABLE email_outbox DROP COLUMN IF EXISTS headers;
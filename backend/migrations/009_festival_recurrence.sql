@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE festivals ADD COLUMN IF NOT EXISTS recurrence JSONB;
+
+-- manual rows are entered or edited by admins and are never overwritten,
+-- generated rows come from the festival's recurrence rule
+ALTER TABLE festival_dates ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'manual';
+
+-- carry over the legacy 2026 columns
+INSERT INTO festival_dates (festival_id, year, start_date, end_date)
+SELECT id, 2026, date_2026_start, date_2026_end
+FROM festivals
+WHERE date_2026_start IS NOT NULL
+ON CONFLICT (festival_id, year) DO NOTHING;
+
+-- +goose Down
+ALTER TABLE festival_dates DROP COLUMN IF EXISTS source;
+ALTER TABLE festivals DROP COLUMN IF EXISTS recurrence;
@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS calendar_token VARCHAR(100);
+
+UPDATE subscriptions
+SET calendar_token = replace(uuid_generate_v4()::text, '-', '') || replace(uuid_generate_v4()::text, '-', '')
+WHERE calendar_token IS NULL;
+
+ALTER TABLE subscriptions ALTER COLUMN calendar_token SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_calendar_token ON subscriptions(calendar_token);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_subscriptions_calendar_token;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS calendar_token;
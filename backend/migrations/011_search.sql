@@ -0,0 +1,42 @@
+-- +goose Up
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent() is only STABLE, which expression indexes don't accept
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION immutable_unaccent(text) RETURNS text AS $$
+    SELECT public.unaccent('public.unaccent', $1)
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION festival_search_vector(name TEXT, summary TEXT, story TEXT, what_to_expect TEXT, practical_info TEXT)
+RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector('english', immutable_unaccent(coalesce(name, ''))), 'A')
+        || setweight(to_tsvector('english', immutable_unaccent(coalesce(summary, ''))), 'B')
+        || setweight(to_tsvector('english', immutable_unaccent(coalesce(story, ''))), 'C')
+        || setweight(to_tsvector('english', immutable_unaccent(coalesce(what_to_expect, ''))), 'C')
+        || setweight(to_tsvector('english', immutable_unaccent(coalesce(practical_info, ''))), 'D')
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION memory_search_vector(content TEXT)
+RETURNS tsvector AS $$
+    SELECT to_tsvector('english', immutable_unaccent(coalesce(content, '')))
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+-- +goose StatementEnd
+
+-- indexed expressions rather than stored columns, so SELECT * rows stay as they are
+CREATE INDEX IF NOT EXISTS idx_festivals_search ON festivals
+    USING GIN (festival_search_vector(name, summary, story, what_to_expect, practical_info));
+
+CREATE INDEX IF NOT EXISTS idx_memories_search ON memories
+    USING GIN (memory_search_vector(content))
+    WHERE status = 'approved';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_memories_search;
+DROP INDEX IF EXISTS idx_festivals_search;
+DROP FUNCTION IF EXISTS memory_search_vector(TEXT);
+DROP FUNCTION IF EXISTS festival_search_vector(TEXT, TEXT, TEXT, TEXT, TEXT);
+DROP FUNCTION IF EXISTS immutable_unaccent(text);
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS memory_reviews (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    memory_id UUID NOT NULL REFERENCES memories(id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    reviewer VARCHAR(100) NOT NULL,
+    reason TEXT,
+    notes TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_memory_reviews_memory ON memory_reviews(memory_id, created_at);
+
+-- the moderation queue reads pending memories oldest first
+CREATE INDEX IF NOT EXISTS idx_memories_pending ON memories(submitted_at, id) WHERE status = 'pending';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_memories_pending;
+DROP TABLE IF EXISTS memory_reviews;
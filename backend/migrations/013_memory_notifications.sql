@@ -0,0 +1,25 @@
+-- +goose Up
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS notify_author BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS author_token VARCHAR(100);
+
+UPDATE memories
+SET author_token = replace(uuid_generate_v4()::text, '-', '') || replace(uuid_generate_v4()::text, '-', '')
+WHERE author_token IS NULL;
+
+ALTER TABLE memories ALTER COLUMN author_token SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_memories_author_token ON memories(author_token);
+
+ALTER TABLE memory_reviews ADD COLUMN IF NOT EXISTS author_message TEXT;
+
+-- addresses that asked not to hear about any of their memories
+CREATE TABLE IF NOT EXISTS memory_author_optouts (
+    email VARCHAR(255) PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS memory_author_optouts;
+ALTER TABLE memory_reviews DROP COLUMN IF EXISTS author_message;
+DROP INDEX IF EXISTS idx_memories_author_token;
+ALTER TABLE memories DROP COLUMN IF EXISTS author_token;
+ALTER TABLE memories DROP COLUMN IF EXISTS notify_author;
@@ -0,0 +1,22 @@
+-- +goose Up
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64);
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS screening_score INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS screening_results JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS flagged BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_memories_content_hash ON memories(content_hash);
+
+-- flagged memories come first in the moderation queue, which orders and pages
+-- by (NOT flagged, submitted_at, id) so both can use this index
+DROP INDEX IF EXISTS idx_memories_pending;
+CREATE INDEX idx_memories_pending ON memories((NOT flagged), submitted_at, id) WHERE status = 'pending';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_memories_pending;
+CREATE INDEX idx_memories_pending ON memories(submitted_at, id) WHERE status = 'pending';
+
+DROP INDEX IF EXISTS idx_memories_content_hash;
+ALTER TABLE memories DROP COLUMN IF EXISTS flagged;
+ALTER TABLE memories DROP COLUMN IF EXISTS screening_results;
+ALTER TABLE memories DROP COLUMN IF EXISTS screening_score;
+ALTER TABLE memories DROP COLUMN IF EXISTS content_hash;
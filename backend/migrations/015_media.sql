@@ -0,0 +1,26 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS media_assets (
+    id UUID PRIMARY KEY,
+    original_filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- the resized copies actually served; the upload itself is not kept
+CREATE TABLE IF NOT EXISTS media_variants (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    asset_id UUID NOT NULL REFERENCES media_assets(id) ON DELETE CASCADE,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    storage_key TEXT NOT NULL UNIQUE,
+    UNIQUE (asset_id, width)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS media_variants;
+DROP TABLE IF EXISTS media_assets;
@@ -0,0 +1,6 @@
+-- +goose Up
+ALTER TABLE festivals ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+UPDATE festivals SET updated_at = created_at WHERE created_at IS NOT NULL;
+
+-- +goose Down
+ALTER TABLE festivals DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE digest_deliveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    period_key VARCHAR(50) NOT NULL,
+    sent_at TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE(subscription_id, period_key)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS digest_deliveries;
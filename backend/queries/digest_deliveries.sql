@@ -0,0 +1,12 @@
+-- name: CreateDigestDelivery :one
+INSERT INTO digest_deliveries (
+    subscription_id, period_key
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (subscription_id, period_key) DO NOTHING
+RETURNING *;
+
+-- name: DeleteDigestDelivery :exec
+DELETE FROM digest_deliveries
+WHERE id = $1;
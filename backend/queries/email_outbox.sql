@@ -0,0 +1,28 @@
+-- name: EnqueueEmail :one
+INSERT INTO email_outbox (
+    to_email, subject, html_body, text_body, headers
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: ClaimDueEmails :many
+UPDATE email_outbox
+SET attempts = attempts + 1, next_attempt_at = NOW() + INTERVAL '5 minutes'
+WHERE id IN (
+    SELECT id FROM email_outbox
+    WHERE status = 'pending' AND next_attempt_at <= NOW()
+    ORDER BY next_attempt_at ASC
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkEmailSent :exec
+UPDATE email_outbox
+SET status = 'sent', sent_at = NOW(), provider_message_id = $2, last_error = NULL
+WHERE id = $1;
+
+-- name: MarkEmailFailed :exec
+UPDATE email_outbox
+SET status = $2, last_error = $3, next_attempt_at = $4
+WHERE id = $1;
@@ -34,7 +34,8 @@ ORDER BY fd.start_date ASC;
 UPDATE festival_dates SET
     start_date = $2,
     end_date = $3,
-    is_tentative = $4
+    is_tentative = $4,
+    source = 'manual'
 WHERE id = $1
 RETURNING *;
 
@@ -45,3 +46,57 @@ WHERE id = $1;
 -- name: DeleteFestivalDatesByFestivalID :exec
 DELETE FROM festival_dates
 WHERE festival_id = $1;
+
+-- name: ListFestivalDatesBetween :many
+SELECT fd.*, f.slug, f.name
+FROM festival_dates fd
+JOIN festivals f ON f.id = fd.festival_id
+WHERE f.is_published = true
+  AND fd.start_date >= sqlc.arg(from_date)::date
+  AND fd.start_date <= sqlc.arg(to_date)::date
+ORDER BY fd.start_date ASC;
+
+-- name: UpsertGeneratedFestivalDate :execrows
+INSERT INTO festival_dates (
+    festival_id, year, start_date, end_date, is_tentative, source
+) VALUES (
+    $1, $2, $3, $4, true, 'generated'
+)
+ON CONFLICT (festival_id, year) DO UPDATE SET
+    start_date = EXCLUDED.start_date,
+    end_date = EXCLUDED.end_date
+WHERE festival_dates.source = 'generated' AND festival_dates.is_tentative = true;
+
+-- name: UpsertComputedFestivalDate :execrows
+INSERT INTO festival_dates (
+    festival_id, year, start_date, end_date, is_tentative, source
+) VALUES (
+    $1, $2, $3, $4, false, 'generated'
+)
+ON CONFLICT (festival_id, year) DO UPDATE SET
+    start_date = EXCLUDED.start_date,
+    end_date = EXCLUDED.end_date,
+    is_tentative = false
+WHERE festival_dates.source = 'generated';
+
+-- name: GetFestivalDateByID :one
+SELECT * FROM festival_dates
+WHERE id = $1;
+
+-- name: ListTentativeFestivalDates :many
+SELECT fd.*, f.slug, f.name
+FROM festival_dates fd
+JOIN festivals f ON f.id = fd.festival_id
+WHERE fd.is_tentative = true
+  AND fd.start_date >= CURRENT_DATE
+ORDER BY fd.start_date ASC;
+
+-- name: ListFestivalDatesForFeed :many
+SELECT fd.*, f.slug, f.name, f.region, f.heritage_type, f.summary
+FROM festival_dates fd
+JOIN festivals f ON f.id = fd.festival_id
+WHERE f.is_published = true
+  AND fd.start_date >= sqlc.arg(from_date)::date
+  AND (sqlc.narg(region)::text IS NULL OR f.region = sqlc.narg(region))
+  AND (sqlc.narg(heritage)::text IS NULL OR f.heritage_type = sqlc.narg(heritage))
+ORDER BY fd.start_date ASC;
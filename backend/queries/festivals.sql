@@ -1,18 +1,3 @@
--- name: ListFestivals :many
-SELECT * FROM festivals
-WHERE is_published = true
-ORDER BY name ASC;
-
--- name: ListFestivalsByRegion :many
-SELECT * FROM festivals
-WHERE is_published = true AND region = $1
-ORDER BY name ASC;
-
--- name: ListFestivalsByHeritage :many
-SELECT * FROM festivals
-WHERE is_published = true AND heritage_type = $1
-ORDER BY name ASC;
-
 -- name: GetFestivalBySlug :one
 SELECT * FROM festivals
 WHERE slug = $1 AND is_published = true;
@@ -44,12 +29,36 @@ UPDATE festivals SET
     how_to_participate = $11,
     practical_info = $12,
     cover_image_url = $13,
-    gallery_images = $14,
-    video_embeds = $15,
-    is_published = $16
+    is_published = $14,
+    updated_at = clock_timestamp()
+WHERE id = $1
+RETURNING *;
+
+-- name: GetFestivalForUpdate :one
+SELECT * FROM festivals
+WHERE id = $1
+FOR UPDATE;
+
+-- name: UpdateFestivalMedia :one
+UPDATE festivals SET
+    gallery_images = $2,
+    video_embeds = $3,
+    updated_at = clock_timestamp()
 WHERE id = $1
 RETURNING *;
 
 -- name: DeleteFestival :exec
 DELETE FROM festivals
 WHERE id = $1;
+
+-- name: ListFestivalsWithRecurrence :many
+SELECT * FROM festivals
+WHERE recurrence IS NOT NULL
+ORDER BY name ASC;
+
+-- name: UpdateFestivalRecurrence :one
+UPDATE festivals SET
+    recurrence = $2,
+    updated_at = clock_timestamp()
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,13 @@
+-- name: ClaimJobRun :one
+INSERT INTO job_runs (job_name, period_key)
+VALUES ($1, $2)
+ON CONFLICT (job_name, period_key) DO UPDATE
+SET started_at = NOW(), finished_at = NULL, error = NULL
+WHERE job_runs.error IS NOT NULL
+   OR (job_runs.finished_at IS NULL AND job_runs.started_at < NOW() - make_interval(secs => sqlc.arg(lease_seconds)::int))
+RETURNING *;
+
+-- name: FinishJobRun :exec
+UPDATE job_runs
+SET finished_at = NOW(), error = $2
+WHERE id = $1;
@@ -0,0 +1,22 @@
+-- name: CreateMediaAsset :one
+INSERT INTO media_assets (id, original_filename, content_type, size_bytes, width, height)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: CreateMediaVariant :one
+INSERT INTO media_variants (asset_id, width, height, content_type, size_bytes, storage_key)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetMediaAsset :one
+SELECT * FROM media_assets
+WHERE id = $1;
+
+-- name: ListMediaVariants :many
+SELECT * FROM media_variants
+WHERE asset_id = $1
+ORDER BY width ASC;
+
+-- name: DeleteMediaAsset :exec
+DELETE FROM media_assets
+WHERE id = $1;
@@ -5,23 +5,61 @@ ORDER BY submitted_at DESC;
 
 -- name: CreateMemory :one
 INSERT INTO memories (
-    festival_id, author_name, author_email, content, year_of_memory
+    festival_id, author_name, author_email, content, year_of_memory,
+    notify_author, author_token, status, content_hash, screening_score,
+    screening_results, flagged
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 ) RETURNING *;
 
+-- name: ContentHashExists :one
+SELECT (COUNT(*) > 0)::boolean AS found
+FROM memories
+WHERE content_hash = sqlc.arg(content_hash)::text
+  AND submitted_at > NOW() - INTERVAL '90 days';
+
 -- name: GetMemoryByID :one
 SELECT * FROM memories
 WHERE id = $1;
 
--- name: UpdateMemoryStatus :exec
+-- name: GetMemoryByAuthorToken :one
+SELECT * FROM memories
+WHERE author_token = $1;
+
+-- name: GetMemoryForUpdate :one
+SELECT * FROM memories
+WHERE id = $1
+FOR UPDATE;
+
+-- name: UpdateMemoryStatus :one
 UPDATE memories
 SET status = $2
-WHERE id = $1;
+WHERE id = $1
+RETURNING *;
 
--- name: ListAllMemories :many
+-- name: ListPendingMemories :many
 SELECT * FROM memories
-ORDER BY submitted_at DESC;
+WHERE status = 'pending'
+  AND (sqlc.narg(after_id)::uuid IS NULL OR (NOT flagged, submitted_at, id) > (NOT sqlc.narg(after_flagged)::boolean, sqlc.narg(after_submitted_at)::timestamptz, sqlc.narg(after_id)))
+ORDER BY (NOT flagged), submitted_at ASC, id ASC
+LIMIT sqlc.arg(max_results)::int;
+
+-- name: ListMemoriesPage :many
+SELECT * FROM memories
+WHERE (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(festival_id)::uuid IS NULL OR festival_id = sqlc.narg(festival_id))
+  AND (sqlc.narg(submitted_from)::date IS NULL OR submitted_at >= sqlc.narg(submitted_from))
+  AND (sqlc.narg(submitted_to)::date IS NULL OR submitted_at < sqlc.narg(submitted_to) + 1)
+  AND (sqlc.narg(after_id)::uuid IS NULL OR (submitted_at, id) < (sqlc.narg(after_submitted_at)::timestamptz, sqlc.narg(after_id)))
+ORDER BY submitted_at DESC, id DESC
+LIMIT sqlc.arg(max_results)::int;
+
+-- name: CountMemories :one
+SELECT COUNT(*) FROM memories
+WHERE (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(festival_id)::uuid IS NULL OR festival_id = sqlc.narg(festival_id))
+  AND (sqlc.narg(submitted_from)::date IS NULL OR submitted_at >= sqlc.narg(submitted_from))
+  AND (sqlc.narg(submitted_to)::date IS NULL OR submitted_at < sqlc.narg(submitted_to) + 1);
 
 -- name: DeleteMemory :exec
 DELETE FROM memories
@@ -0,0 +1,21 @@
+-- name: CreateMemoryReview :one
+INSERT INTO memory_reviews (
+    memory_id, from_status, to_status, reviewer, reason, notes, author_message
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: ListMemoryReviews :many
+SELECT * FROM memory_reviews
+WHERE memory_id = $1
+ORDER BY created_at ASC;
+
+-- name: CreateMemoryAuthorOptout :exec
+INSERT INTO memory_author_optouts (email)
+VALUES (lower(sqlc.arg(email)::text))
+ON CONFLICT (email) DO NOTHING;
+
+-- name: IsMemoryAuthorOptedOut :one
+SELECT (COUNT(*) > 0)::boolean AS opted_out
+FROM memory_author_optouts
+WHERE email = lower(sqlc.arg(email)::text);
@@ -0,0 +1,12 @@
+-- name: CreateReminderDelivery :one
+INSERT INTO reminder_deliveries (
+    subscription_id, festival_date_id, days_before
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (subscription_id, festival_date_id, days_before) DO NOTHING
+RETURNING *;
+
+-- name: DeleteReminderDelivery :exec
+DELETE FROM reminder_deliveries
+WHERE id = $1;
@@ -0,0 +1,25 @@
+-- name: SearchFestivals :many
+SELECT f.id, f.slug, f.name, f.summary,
+    ts_rank(festival_search_vector(f.name, f.summary, f.story, f.what_to_expect, f.practical_info), q.query)::real AS rank,
+    ts_headline('english', concat_ws(' ', f.summary, f.story, f.what_to_expect, f.practical_info), q.query,
+        'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10')::text AS snippet
+FROM festivals f
+CROSS JOIN to_tsquery('english', immutable_unaccent(sqlc.arg(query)::text)) AS q(query)
+WHERE f.is_published = true
+  AND festival_search_vector(f.name, f.summary, f.story, f.what_to_expect, f.practical_info) @@ q.query
+ORDER BY rank DESC, f.name ASC
+LIMIT sqlc.arg(max_results)::int;
+
+-- name: SearchMemories :many
+SELECT m.id, m.festival_id, f.slug AS festival_slug, f.name AS festival_name, m.author_name, m.year_of_memory,
+    ts_rank(memory_search_vector(m.content), q.query)::real AS rank,
+    ts_headline('english', m.content, q.query,
+        'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10')::text AS snippet
+FROM memories m
+JOIN festivals f ON f.id = m.festival_id
+CROSS JOIN to_tsquery('english', immutable_unaccent(sqlc.arg(query)::text)) AS q(query)
+WHERE m.status = 'approved'
+  AND f.is_published = true
+  AND memory_search_vector(m.content) @@ q.query
+ORDER BY rank DESC, m.submitted_at DESC
+LIMIT sqlc.arg(max_results)::int;
@@ -1,8 +1,8 @@
 -- name: CreateSubscription :one
 INSERT INTO subscriptions (
-    email, digest_weekly, festival_reminders, confirmation_token, unsubscribe_token
+    email, digest_weekly, festival_reminders, confirmation_token, unsubscribe_token, confirmation_expires_at, calendar_token
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6, $7
 ) RETURNING *;
 
 -- name: GetSubscriptionByEmail :one
@@ -19,9 +19,19 @@ WHERE unsubscribe_token = $1;
 
 -- name: ConfirmSubscription :exec
 UPDATE subscriptions
-SET confirmed = true, confirmation_token = NULL
+SET confirmed = true, confirmation_token = NULL, confirmation_expires_at = NULL
 WHERE id = $1;
 
+-- name: RefreshConfirmationToken :one
+UPDATE subscriptions
+SET confirmation_token = $2, confirmation_expires_at = $3
+WHERE id = $1 AND confirmed = false
+RETURNING *;
+
+-- name: DeleteExpiredUnconfirmedSubscriptions :execrows
+DELETE FROM subscriptions
+WHERE confirmed = false AND confirmation_expires_at < NOW();
+
 -- name: DeleteSubscription :exec
 DELETE FROM subscriptions
 WHERE id = $1;
@@ -30,6 +40,43 @@ WHERE id = $1;
 SELECT * FROM subscriptions
 WHERE confirmed = true AND digest_weekly = true;
 
--- name: ListAllSubscriptions :many
+-- name: ListSubscriptionsPage :many
 SELECT * FROM subscriptions
-ORDER BY created_at DESC;
+WHERE (sqlc.narg(confirmed)::boolean IS NULL OR confirmed = sqlc.narg(confirmed))
+  AND (sqlc.narg(digest_weekly)::boolean IS NULL OR digest_weekly = sqlc.narg(digest_weekly))
+  AND (sqlc.narg(created_from)::date IS NULL OR created_at >= sqlc.narg(created_from))
+  AND (sqlc.narg(created_to)::date IS NULL OR created_at < sqlc.narg(created_to) + 1)
+  AND (sqlc.narg(after_id)::uuid IS NULL OR (created_at, id) < (sqlc.narg(after_created_at)::timestamptz, sqlc.narg(after_id)))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(max_results)::int;
+
+-- name: CountSubscriptions :one
+SELECT COUNT(*) FROM subscriptions
+WHERE (sqlc.narg(confirmed)::boolean IS NULL OR confirmed = sqlc.narg(confirmed))
+  AND (sqlc.narg(digest_weekly)::boolean IS NULL OR digest_weekly = sqlc.narg(digest_weekly))
+  AND (sqlc.narg(created_from)::date IS NULL OR created_at >= sqlc.narg(created_from))
+  AND (sqlc.narg(created_to)::date IS NULL OR created_at < sqlc.narg(created_to) + 1);
+
+-- name: ListConfirmedWithReminders :many
+SELECT * FROM subscriptions
+WHERE confirmed = true AND festival_reminders <> '[]'::jsonb;
+
+-- name: UpdateSubscriptionReminders :one
+UPDATE subscriptions
+SET festival_reminders = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: GetSubscriptionByID :one
+SELECT * FROM subscriptions
+WHERE id = $1;
+
+-- name: GetSubscriptionByCalendarToken :one
+SELECT * FROM subscriptions
+WHERE calendar_token = $1 AND confirmed = true;
+
+-- name: UpdateCalendarToken :one
+UPDATE subscriptions SET
+    calendar_token = $2
+WHERE id = $1
+RETURNING *;
@@ -14,12 +14,20 @@ interface CreateMemoryRequest {
     authorEmail: string;
     content: string;
     yearOfMemory: string;
+    /** honeypot, left empty by people */
+    website?: string;
 }
 
 interface SubscribeRequest {
     email: string;
 }
 
+interface Page<T> {
+    data: T[];
+    nextCursor?: string;
+    hasMore: boolean;
+}
+
 /**
  * Generic fetch wrapper with error handling
  */
@@ -59,11 +67,21 @@ async function apiFetch<T>(endpoint: string, options?: RequestInit): Promise<T>
  */
 export const festivalsApi = {
     /**
-     * Get all festivals
+     * Get all festivals, following nextCursor through every page
      * GET /api/festivals
      */
     list: async (): Promise<Festival[]> => {
-        return apiFetch<Festival[]>('/api/festivals');
+        const festivals: Festival[] = [];
+        let cursor: string | undefined;
+
+        do {
+            const query = cursor ? `&cursor=${encodeURIComponent(cursor)}` : '';
+            const page = await apiFetch<Page<Festival>>(`/api/festivals?limit=100${query}`);
+            festivals.push(...page.data);
+            cursor = page.hasMore ? page.nextCursor : undefined;
+        } while (cursor);
+
+        return festivals;
     },
 
     /**
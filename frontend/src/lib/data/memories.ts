@@ -161,6 +161,7 @@ export async function submitMemory(memory: {
     authorEmail: string;
     content: string;
     yearOfMemory: string;
+    website?: string;
 }): Promise<Memory> {
     if (config.useApi) {
         return await memoriesApi.create(memory);
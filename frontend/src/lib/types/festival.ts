@@ -25,20 +25,38 @@ export interface Festival {
     howToParticipate: string | null;
     practicalInfo: string | null;
     coverImageUrl: string | null;
-    galleryImages: string[];
-    videoEmbeds: string[];
+    galleryImages: GalleryImage[];
+    videoEmbeds: VideoEmbed[];
     isPublished: boolean;
     createdAt: string;
+    updatedAt?: string;
+}
+
+export interface GalleryImage {
+    id: string;
+    url: string;
+    caption: string;
+    credit: string;
+    alt: string;
+    order: number;
+}
+
+export interface VideoEmbed {
+    id: string;
+    provider: 'youtube' | 'vimeo';
+    videoId: string;
+    title: string;
+    order: number;
 }
 
 export interface Memory {
     id: string;
     festivalId: string;
     authorName: string | null;
-    authorEmail: string | null;
+    authorEmail?: string | null; // admin responses only
     content: string;
     yearOfMemory: string | null;
-    status: 'pending' | 'approved' | 'rejected';
+    status?: 'pending' | 'approved' | 'rejected'; // admin responses only
     submittedAt: string;
 }
 